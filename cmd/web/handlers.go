@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"foresee/cmd/web/viewmodels"
 	"foresee/internal/models"
+	"foresee/internal/oracle"
+	"foresee/internal/payout"
+	"foresee/internal/recurrence"
 	"foresee/internal/services"
 	"foresee/internal/validator"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 )
@@ -26,11 +31,41 @@ type loginForm struct {
 }
 
 type createMarketForm struct {
-	Title               string `form:"title"`
-	Description         string `form:"description"`
-	Category            string `form:"category"`
-	ResolverType        string `form:"resolver_type"`
-	ExpiresAt           string `form:"expires_at"`
+	Title               string   `form:"title"`
+	Description         string   `form:"description"`
+	Category            string   `form:"category"`
+	ResolverType        string   `form:"resolver_type"`
+	ExpiresAt           string   `form:"expires_at"`
+	GroupID             string   `form:"group_id"`
+	DisputeWindowHours  int      `form:"dispute_window_hours"`
+	Liquidity           int      `form:"liquidity"`
+	FromTreasury        bool     `form:"from_treasury"`
+	Kind                string   `form:"kind"`
+	ScalarLower         *float64 `form:"scalar_lower"`
+	ScalarUpper         *float64 `form:"scalar_upper"`
+	ScalarLog           bool     `form:"scalar_log"`
+	BucketLower         *float64 `form:"bucket_lower"`
+	BucketUpper         *float64 `form:"bucket_upper"`
+	BucketSize          *float64 `form:"bucket_size"`
+	BucketStart         string   `form:"bucket_start"`
+	BucketEnd           string   `form:"bucket_end"`
+	BucketDays          int      `form:"bucket_days"`
+	ConditionOutcomeID  string   `form:"condition_outcome_id"`
+	Repeat              string   `form:"repeat"`
+	EventID             string   `form:"event_id"`
+	EventGroup          string   `form:"event_group"`
+	OracleSource        string   `form:"oracle_source"`
+	OracleTarget        string   `form:"oracle_target"`
+	OraclePath          string   `form:"oracle_path"`
+	OracleOperator      string   `form:"oracle_operator"`
+	OracleThreshold     *float64 `form:"oracle_threshold"`
+	ResolverUsername    string   `form:"resolver_username"`
+	validator.Validator `form:"-"`
+}
+
+type profileSettingsForm struct {
+	PublicBets          bool   `form:"public_bets"`
+	Timezone            string `form:"timezone"`
 	validator.Validator `form:"-"`
 }
 
@@ -40,13 +75,53 @@ type placeBetForm struct {
 	validator.Validator
 }
 
+// resolveMarketForm either names a single winning outcome or, when Split is
+// set, gives each outcome a whole percentage of the pool keyed by outcome ID.
+// Markets answered with a number are resolved with Value instead, or Date
+// for date markets.
 type resolveMarketForm struct {
-	OutcomeID string `form:"outcome_id"`
+	OutcomeID string         `form:"outcome_id"`
+	Split     bool           `form:"split"`
+	Shares    map[string]int `form:"shares"`
+	Value     *float64       `form:"value"`
+	Date      string         `form:"date"`
 	validator.Validator
 }
 
 func (app *application) home(w http.ResponseWriter, r *http.Request) {
-	markets, err := app.marketService.Latest()
+	// ?event= narrows the listing to one event's markets.
+	var eventFilter *models.Event
+	if param := r.URL.Query().Get("event"); param != "" {
+		id, err := uuid.Parse(param)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		event, err := app.eventPages.Find(id)
+		if err != nil {
+			if errors.Is(err, models.ErrNoRecord) {
+				http.NotFound(w, r)
+				return
+			}
+			app.serverError(w, err)
+			return
+		}
+		eventFilter = &event
+	}
+
+	var eventID *uuid.UUID
+	if eventFilter != nil {
+		eventID = &eventFilter.ID
+	}
+
+	markets, err := app.marketService.Latest(app.viewerID(r), eventID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	events, err := app.eventPages.Upcoming()
 	if err != nil {
 		app.serverError(w, err)
 		return
@@ -62,6 +137,8 @@ func (app *application) home(w http.ResponseWriter, r *http.Request) {
 
 	data := app.newTemplateData(r)
 	data.Markets = marketViews
+	data.Events = events
+	data.EventFilter = eventFilter
 
 	app.render(w, http.StatusOK, "home.html", data)
 }
@@ -175,16 +252,225 @@ func (app *application) account(w http.ResponseWriter, r *http.Request) {
 	}
 	data.PendingResolutions = marketsPendingResolution
 
+	user, err := app.users.Get(userID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+	data.User = user
+
+	achievements, err := app.achievements.ForUser(userID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+	data.Achievements = achievements
+
+	feeIncome, err := app.fees.IncomeFor(userID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+	data.FeeIncome = feeIncome
+
 	data.BetHistory = userBetHistory
 	app.render(w, http.StatusOK, "account.html", data)
 }
 
+func (app *application) profileSettingsPost(w http.ResponseWriter, r *http.Request) {
+	var form profileSettingsForm
+	err := app.decodePostForm(r, &form)
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	form.CheckField(validator.IsTimezone(form.Timezone), "timezone", "The timezone must be a valid IANA name such as Europe/Madrid")
+	if !form.Valid() {
+		app.sessionManager.Put(r.Context(), "flash_error", form.FieldErrors["timezone"])
+		http.Redirect(w, r, "/account", http.StatusSeeOther)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	err = app.users.UpdateSettings(userID, form.PublicBets, form.Timezone)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "Your profile settings have been saved")
+	http.Redirect(w, r, "/account", http.StatusSeeOther)
+}
+
+func (app *application) viewProfile(w http.ResponseWriter, r *http.Request) {
+	profile, err := app.profileService.Get(r.PathValue("username"))
+	if err != nil {
+		if errors.Is(err, models.ErrNoRecord) {
+			http.NotFound(w, r)
+			return
+		}
+
+		app.serverError(w, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data.Profile = profile
+	app.render(w, http.StatusOK, "profile.html", data)
+}
+
+func (app *application) viewLeaderboard(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if query.Get("season") != "" {
+		seasonID, err := uuid.Parse(query.Get("season"))
+		if err != nil {
+			app.clientError(w, http.StatusBadRequest)
+			return
+		}
+
+		leaderboard, err := app.leaderboard.GetSeason(seasonID)
+		if err != nil {
+			if errors.Is(err, models.ErrNoRecord) {
+				http.NotFound(w, r)
+				return
+			}
+
+			app.serverError(w, err)
+			return
+		}
+
+		data := app.newTemplateData(r)
+		data.Leaderboard = leaderboard
+		data.LeaderboardSorts = models.AllLeaderboardSorts()
+		app.render(w, http.StatusOK, "leaderboard.html", data)
+		return
+	}
+
+	period := query.Get("period")
+	if period == "" {
+		period = models.PeriodAllTime
+	}
+
+	category := query.Get("category")
+	if category != "" && !validator.PermittedValue(models.Category(category), models.AllCategories()...) {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	sort := models.LeaderboardSort(query.Get("sort"))
+	if sort == "" {
+		sort = models.SortByProfit
+	}
+
+	if !validator.PermittedValue(sort, models.AllLeaderboardSorts()...) {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	leaderboard, err := app.leaderboard.Get(period, category, sort)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data.Leaderboard = leaderboard
+	data.LeaderboardSorts = models.AllLeaderboardSorts()
+	app.render(w, http.StatusOK, "leaderboard.html", data)
+}
+
 func (app *application) createMarket(w http.ResponseWriter, r *http.Request) {
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	groups, err := app.groupService.ForUser(userID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	fees, err := app.fees.Schedule()
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	events, err := app.eventPages.Upcoming()
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	form := createMarketForm{
+		GroupID: r.URL.Query().Get("group"),
+		EventID: r.URL.Query().Get("event"),
+		Kind:    string(models.KindBinary),
+	}
+
+	condition, err := app.marketCondition(r.URL.Query().Get("condition"), userID)
+	if errors.Is(err, services.ErrConditionOutcome) {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	if condition != nil {
+		form.ConditionOutcomeID = condition.OutcomeID
+		form.GroupID = condition.GroupID
+	}
+
 	data := app.newTemplateData(r)
-	data.Form = createMarketForm{}
+	data.Groups = groups
+	data.Events = events
+	data.FeeSchedule = fees
+	data.Condition = condition
+	data.Form = form
 	app.render(w, http.StatusOK, "create_market.html", data)
 }
 
+// marketCondition describes the outcome a new market would depend on, or
+// returns nil when outcomeID is empty.
+func (app *application) marketCondition(outcomeID string, userID uuid.UUID) (*viewmodels.ConditionView, error) {
+	if outcomeID == "" {
+		return nil, nil
+	}
+
+	id, err := uuid.Parse(outcomeID)
+	if err != nil {
+		return nil, services.ErrConditionOutcome
+	}
+
+	m, o, err := app.marketService.Condition(id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &viewmodels.ConditionView{
+		MarketID:     m.ID.String(),
+		MarketTitle:  m.Title,
+		Status:       m.Status,
+		OutcomeID:    o.ID.String(),
+		OutcomeLabel: o.Label,
+	}
+	if m.GroupID != nil {
+		view.GroupID = m.GroupID.String()
+	}
+
+	return view, nil
+}
+
 func (app *application) createMarketPost(w http.ResponseWriter, r *http.Request) {
 	var form createMarketForm
 
@@ -200,52 +486,318 @@ func (app *application) createMarketPost(w http.ResponseWriter, r *http.Request)
 	form.CheckField(validator.PermittedValue(models.Category(form.Category), models.AllCategories()...), "category", "The category must be valid")
 	form.CheckField(validator.NotBlank(form.ResolverType), "resolverType", "Description cannot be empty")
 	form.CheckField(validator.PermittedValue(models.ResolverType(form.ResolverType), models.AllResolverTypes()...), "resolverType", "The resolver type must be valid")
-	form.CheckField(validator.NotBlank(form.ExpiresAt), "expiresAt", "The expiry date must be fulfilled")
-	form.CheckField(validator.IsValidDate(form.ExpiresAt), "expiresAt", "The expiry date must be valid and must not be in the past")
+	// A series works out when each of its markets expires from its rule.
+	if form.Repeat == "" {
+		form.CheckField(validator.NotBlank(form.ExpiresAt), "expiresAt", "The expiry date must be fulfilled")
+		form.CheckField(validator.IsValidDate(form.ExpiresAt), "expiresAt", "The expiry date must be valid and must not be in the past")
+	}
+	form.CheckField(validator.PermittedValue(form.DisputeWindowHours, models.AllDisputeWindows()...), "disputeWindowHours", "The dispute window must be valid")
+	form.CheckField(validator.MinNumber(form.Liquidity, 0), "liquidity", "Liquidity cannot be negative")
+	form.CheckField(validator.PermittedValue(models.MarketKind(form.Kind), models.AllMarketKinds()...), "kind", "The market type must be valid")
+	if form.Kind == string(models.KindScalar) {
+		form.CheckField(form.ScalarLower != nil, "scalarLower", "The lower bound must be set")
+		form.CheckField(form.ScalarUpper != nil, "scalarUpper", "The upper bound must be set")
+	}
+	if form.Kind == string(models.KindBucket) {
+		form.CheckField(form.BucketLower != nil && form.BucketUpper != nil, "bucketRange", "The range must have a start and an end")
+		form.CheckField(form.BucketSize != nil, "bucketRange", "The bucket size must be set")
+	}
+	if form.Kind == string(models.KindDate) {
+		form.CheckField(validator.NotBlank(form.BucketStart) && validator.NotBlank(form.BucketEnd), "bucketRange", "The range must have a start and an end date")
+		form.CheckField(validator.MinNumber(form.BucketDays, 1), "bucketRange", "Buckets must be at least one day long")
+	}
+
+	var oracleConfig *oracle.Config
+	if form.ResolverType == string(models.ResolverOracle) {
+		form.CheckField(validator.PermittedValue(oracle.Kind(form.OracleSource), oracle.AllKinds()...), "oracle", "The data source must be valid")
+		form.CheckField(validator.NotBlank(form.OracleTarget), "oracle", "The data source needs an endpoint or a script")
+		if form.Kind == string(models.KindBinary) {
+			form.CheckField(form.OracleThreshold != nil, "oracleRule", "Yes/no markets need a value to compare the reading with")
+		}
+
+		oracleConfig = &oracle.Config{
+			Source:   oracle.Kind(form.OracleSource),
+			Target:   strings.TrimSpace(form.OracleTarget),
+			Path:     strings.TrimSpace(form.OraclePath),
+			Operator: oracle.Operator(form.OracleOperator),
+		}
+		if form.OracleThreshold != nil {
+			oracleConfig.Threshold = *form.OracleThreshold
+		}
+	}
+
+	form.ResolverUsername = strings.TrimSpace(form.ResolverUsername)
+	if form.ResolverType == string(models.ResolverDesignated) {
+		form.CheckField(validator.NotBlank(form.ResolverUsername), "resolverUsername", "Enter the username of the user who will resolve the market")
+	}
+
+	var groupID *uuid.UUID
+	if form.GroupID != "" {
+		id, err := uuid.Parse(form.GroupID)
+		form.CheckField(err == nil, "groupID", "The group must be valid")
+		groupID = &id
+	}
+
+	var eventID *uuid.UUID
+	if form.EventID != "" {
+		id, err := uuid.Parse(form.EventID)
+		form.CheckField(err == nil, "eventID", "The event must be valid")
+		eventID = &id
+	}
+	form.EventGroup = strings.TrimSpace(form.EventGroup)
+	form.CheckField(validator.MaxChars(form.EventGroup, 60), "eventGroup", "The group name cannot be longer than 60 characters")
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	condition, err := app.marketCondition(form.ConditionOutcomeID, userID)
+	if errors.Is(err, services.ErrConditionOutcome) {
+		form.AddNonFieldError(err.Error())
+	} else if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	var conditionOutcomeID *uuid.UUID
+	if condition != nil {
+		id := uuid.MustParse(condition.OutcomeID)
+		conditionOutcomeID = &id
+	}
+
+	var redirectTo string
+	if form.Valid() {
+		nm := services.NewMarket{
+			Title:        form.Title,
+			Description:  form.Description,
+			Category:     form.Category,
+			ResolverType: form.ResolverType,
+			ExpiresAt:    form.ExpiresAt,
+			CreatedBy:    userID,
+			GroupID:      groupID,
+
+			DisputeWindowHours: form.DisputeWindowHours,
+			Liquidity:          form.Liquidity,
+			FromTreasury:       form.FromTreasury,
+
+			Kind:        form.Kind,
+			ScalarLower: form.ScalarLower,
+			ScalarUpper: form.ScalarUpper,
+			ScalarLog:   form.ScalarLog,
+			BucketLower: form.BucketLower,
+			BucketUpper: form.BucketUpper,
+			BucketSize:  form.BucketSize,
+			BucketStart: form.BucketStart,
+			BucketEnd:   form.BucketEnd,
+			BucketDays:  form.BucketDays,
+
+			ConditionOutcomeID: conditionOutcomeID,
+
+			EventID:    eventID,
+			EventGroup: form.EventGroup,
+
+			Oracle:           oracleConfig,
+			ResolverUsername: form.ResolverUsername,
+		}
+
+		var id uuid.UUID
+		if form.Repeat == "" {
+			id, err = app.marketService.Create(nm)
+			redirectTo = fmt.Sprintf("/markets/%s", id)
+		} else {
+			id, err = app.series.Create(services.NewSeries{NewMarket: nm, Rule: form.Repeat})
+			redirectTo = fmt.Sprintf("/series/%s", id)
+		}
+
+		if errors.Is(err, services.ErrNotGroupMember) {
+			form.AddFieldError("groupID", "You can only create markets in groups you belong to")
+		} else if errors.Is(err, services.ErrInsufficientBalance) {
+			form.AddFieldError("liquidity", "You do not have enough coins to seed every outcome with this much")
+		} else if errors.Is(err, services.ErrInsufficientTreasury) || errors.Is(err, services.ErrTreasuryNotAdmin) || errors.Is(err, services.ErrLiquidityAmount) {
+			form.AddFieldError("liquidity", err.Error())
+		} else if errors.Is(err, services.ErrScalarBounds) {
+			form.AddFieldError("scalarUpper", err.Error())
+		} else if errors.Is(err, services.ErrBucketRange) || errors.Is(err, services.ErrBucketDate) {
+			form.AddFieldError("bucketRange", err.Error())
+		} else if errors.Is(err, services.ErrConditionOutcome) || errors.Is(err, services.ErrConditionGroup) {
+			form.AddNonFieldError(err.Error())
+		} else if errors.Is(err, services.ErrResolverNotFound) || errors.Is(err, services.ErrResolverSelf) || errors.Is(err, services.ErrResolverNotMember) || errors.Is(err, services.ErrResolverSuspended) {
+			form.AddFieldError("resolverUsername", err.Error())
+		} else if errors.Is(err, services.ErrOracleRequired) || errors.Is(err, oracle.ErrUnknownSource) || errors.Is(err, oracle.ErrInvalidURL) || errors.Is(err, oracle.ErrInvalidScript) {
+			form.AddFieldError("oracle", err.Error())
+		} else if errors.Is(err, oracle.ErrInvalidPath) {
+			form.AddFieldError("oraclePath", err.Error())
+		} else if errors.Is(err, oracle.ErrInvalidOperator) {
+			form.AddFieldError("oracleRule", err.Error())
+		} else if errors.Is(err, recurrence.ErrInvalidRule) || errors.Is(err, recurrence.ErrNeverOccurs) || errors.Is(err, recurrence.ErrTooFrequent) || errors.Is(err, services.ErrSeriesKind) || errors.Is(err, services.ErrSeriesResolver) {
+			form.AddFieldError("repeat", err.Error())
+		} else if errors.Is(err, services.ErrEventNotFound) {
+			form.AddFieldError("eventID", err.Error())
+		} else if errors.Is(err, services.ErrEventGroupKind) {
+			form.AddFieldError("eventGroup", err.Error())
+		} else if errors.Is(err, services.ErrUserSuspended) {
+			form.AddNonFieldError(err.Error())
+		} else if err != nil {
+			app.serverError(w, err)
+			return
+		}
+	}
 
 	if !form.Valid() {
+		groups, err := app.groupService.ForUser(userID)
+		if err != nil {
+			app.serverError(w, err)
+			return
+		}
+
+		events, err := app.eventPages.Upcoming()
+		if err != nil {
+			app.serverError(w, err)
+			return
+		}
+
+		fees, err := app.fees.Schedule()
+		if err != nil {
+			app.serverError(w, err)
+			return
+		}
+
 		data := app.newTemplateData(r)
+		data.Groups = groups
+		data.Events = events
+		data.FeeSchedule = fees
+		data.Condition = condition
 		data.Form = form
 		app.render(w, http.StatusUnprocessableEntity, "create_market.html", data)
 		return
 	}
 
-	id, err := app.getUserId(r)
+	if form.ResolverType == string(models.ResolverDesignated) {
+		app.sessionManager.Put(r.Context(), "flash", fmt.Sprintf("The market opens for bets once %s agrees to resolve it", form.ResolverUsername))
+	}
+
+	http.Redirect(w, r, redirectTo, http.StatusSeeOther)
+}
+
+func (app *application) viewMarket(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+	}
+
+	m, err := app.marketService.Get(id)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	// Group markets are reported as missing to outsiders so their titles
+	// do not leak.
+	viewerID := app.viewerID(r)
+	allowed, err := app.marketService.CanAccess(m, viewerID)
 	if err != nil {
 		app.serverError(w, err)
 		return
 	}
 
-	err = app.marketService.Create(
-		form.Title,
-		form.Description,
-		form.Category,
-		form.ResolverType,
-		form.ExpiresAt,
-		id,
-	)
+	// Moderators can still open hidden markets to review them.
+	if !allowed && m.HiddenAt != nil && viewerID != uuid.Nil {
+		role, err := app.users.Role(viewerID)
+		if err != nil {
+			app.serverError(w, err)
+			return
+		}
+		allowed = role.CanModerate()
+	}
+
+	if !allowed {
+		http.NotFound(w, r)
+		return
+	}
 
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	comments, err := app.comments.ForMarket(m.ID, page)
 	if err != nil {
 		app.serverError(w, err)
+		return
 	}
 
-	http.Redirect(w, r, "/markets", http.StatusSeeOther)
-}
+	revisions, err := app.marketService.History(m.ID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
 
-func (app *application) viewMarket(w http.ResponseWriter, r *http.Request) {
-	id, err := uuid.Parse(r.PathValue("id"))
+	disputes, err := app.disputes.ForMarket(m.ID)
 	if err != nil {
-		app.clientError(w, http.StatusBadRequest)
+		app.serverError(w, err)
+		return
 	}
 
-	m, err := app.marketService.Get(id)
+	canDispute, err := app.disputes.CanDispute(m, disputes, viewerID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	corrections, err := app.marketService.CorrectionHistory(m.ID)
 	if err != nil {
 		app.serverError(w, err)
+		return
+	}
+
+	conditionals, err := app.marketService.ConditionalOn(m.ID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	var liquidity []models.LiquidityPosition
+	if m.Liquidity > 0 {
+		liquidity, err = app.marketService.LiquidityPositions(m.ID)
+		if err != nil {
+			app.serverError(w, err)
+			return
+		}
+	}
+
+	var marketOracle *models.Oracle
+	if m.ResolverType == models.ResolverOracle {
+		o, err := app.oracles.ForMarket(m.ID)
+		if err != nil {
+			app.serverError(w, err)
+			return
+		}
+
+		if o.CheckedAt != nil {
+			checkedAt := o.CheckedAt.In(app.location)
+			o.CheckedAt = &checkedAt
+		}
+		marketOracle = &o
+	}
+
+	var flagged []models.Bet
+	if m.IntegrityRule == models.IntegrityFlag {
+		flagged, err = app.betService.Flagged(m.ID)
+		if err != nil {
+			app.serverError(w, err)
+			return
+		}
 	}
 
 	data := app.newTemplateData(r)
 	data.Market = viewmodels.NewMarketView(m, app.location)
-	app.infoLog.Printf("%q", data.Market.Outcomes)
+	data.Oracle = marketOracle
+	data.Liquidity = liquidity
+	data.FlaggedBets = flagged
+	data.Comments = comments
+	data.Revisions = revisions
+	data.Disputes = disputes
+	data.CanDispute = canDispute
+	data.Corrections = corrections
+	data.Conditionals = viewmodels.NewConditionalViews(conditionals)
 	data.Form = placeBetForm{}
 	app.render(w, http.StatusOK, "detail_market.html", data)
 }
@@ -257,12 +809,11 @@ func (app *application) dailyClaimPost(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s := services.UserService{Users: app.users}
 	redirectTo := r.Header.Get("Referer")
 	if redirectTo == "" {
 		redirectTo = "/"
 	}
-	err = s.ClaimDailyReward(id)
+	claim, err := app.userService.ClaimDailyReward(id)
 	if err != nil {
 
 		if errors.Is(err, services.ErrDailyRewardNotAvailable) {
@@ -272,9 +823,15 @@ func (app *application) dailyClaimPost(w http.ResponseWriter, r *http.Request) {
 		}
 
 		app.serverError(w, err)
+		return
 	}
 
-	app.sessionManager.Put(r.Context(), "flash", fmt.Sprintf("Your reward of %d has been added to your balance", services.DailyRewardAmmount))
+	message := fmt.Sprintf("Your reward of %d has been added to your balance", claim.Amount)
+	if claim.Streak > 1 {
+		message = fmt.Sprintf("%s, %d day streak!", message, claim.Streak)
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", message)
 	http.Redirect(w, r, redirectTo, http.StatusSeeOther)
 }
 
@@ -348,17 +905,93 @@ func (app *application) resolveMarket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if *m.ResolverRef != userID {
+	allowed, err := app.marketService.CanResolve(m, userID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	if !allowed {
 		app.clientError(w, http.StatusForbidden)
 		return
 	}
 
+	previews, err := app.marketService.PreviewOutcomes(m)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
 	data := app.newTemplateData(r)
 	data.Market = viewmodels.NewMarketView(m, app.location)
+	data.OutcomePreviews = viewmodels.NewOutcomePreviewViews(previews, data.Market.Outcomes)
 	data.Form = resolveMarketForm{}
 	app.render(w, http.StatusOK, "resolve_market.html", data)
 }
 
+// resolutionShares turns a resolve form into outcome shares in basis points,
+// recording any problems on the form. It writes a 400 response and reports
+// false when an outcome ID is malformed.
+func (app *application) resolutionShares(w http.ResponseWriter, form *resolveMarketForm, m models.Market) (map[uuid.UUID]int, bool) {
+	shares := make(map[uuid.UUID]int)
+
+	if m.Kind.ResolvedByValue() {
+		if m.Kind == models.KindDate {
+			form.CheckField(validator.NotBlank(form.Date), "value", "The date must not be empty")
+			if form.Valid() {
+				value, err := services.DateValue(form.Date)
+				form.CheckField(err == nil, "value", "The date must be valid")
+				form.Value = &value
+			}
+		}
+
+		form.CheckField(form.Value != nil, "value", "The value must not be empty")
+		if !form.Valid() {
+			return shares, true
+		}
+
+		shares, err := services.ValueShares(m, *form.Value)
+		if err != nil {
+			form.AddFieldError("value", err.Error())
+		}
+
+		return shares, true
+	}
+
+	if !form.Split {
+		form.CheckField(validator.NotBlank(form.OutcomeID), "outcome_id", "The outcome must not be empty")
+		if !form.Valid() {
+			return shares, true
+		}
+
+		outcomeID, err := uuid.Parse(form.OutcomeID)
+		if err != nil {
+			app.clientError(w, http.StatusBadRequest)
+			return nil, false
+		}
+
+		return services.FullShare(outcomeID), true
+	}
+
+	total := 0
+	for id, pct := range form.Shares {
+		outcomeID, err := uuid.Parse(id)
+		if err != nil {
+			app.clientError(w, http.StatusBadRequest)
+			return nil, false
+		}
+
+		form.CheckField(pct >= 0 && pct <= 100, "shares", "Each share must be between 0% and 100%")
+		if pct > 0 {
+			shares[outcomeID] = pct * payout.FullShare / 100
+		}
+		total += pct
+	}
+	form.CheckField(total == 100, "shares", "The shares must add up to 100%")
+
+	return shares, true
+}
+
 func (app *application) resolveMarketPost(w http.ResponseWriter, r *http.Request) {
 	marketID, err := uuid.Parse(r.PathValue("id"))
 	if err != nil {
@@ -373,38 +1006,133 @@ func (app *application) resolveMarketPost(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	form.Validator.CheckField(validator.NotBlank(form.OutcomeID), "outcome_id", "The outcome must not be empty")
-	if !form.Valid() {
-		data := app.newTemplateData(r)
-		data.Form = form
-		m, err := app.marketService.Get(marketID)
-		if err != nil {
+	m, err := app.marketService.Get(marketID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	shares, ok := app.resolutionShares(w, &form, m)
+	if !ok {
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	if form.Valid() {
+		if m.Kind.ResolvedByValue() {
+			err = app.marketService.ResolveValue(marketID, userID, *form.Value)
+		} else {
+			err = app.marketService.ResolveMarket(marketID, userID, shares)
+		}
+		if errors.Is(err, services.ErrInvalidShares) {
+			form.AddFieldError("shares", err.Error())
+		} else if errors.Is(err, services.ErrInvalidValue) {
+			form.AddFieldError("value", err.Error())
+		} else if errors.Is(err, services.ErrConditionPending) || errors.Is(err, services.ErrConditionNotMet) {
+			form.AddNonFieldError(err.Error())
+		} else if errors.Is(err, models.ErrUserNotAuthorized) {
+			app.clientError(w, http.StatusForbidden)
+			return
+		} else if err != nil {
 			app.serverError(w, err)
 			return
 		}
+	}
+
+	if !form.Valid() {
+		data := app.newTemplateData(r)
+		data.Form = form
 		data.Market = viewmodels.NewMarketView(m, app.location)
 		app.render(w, http.StatusUnprocessableEntity, "resolve_market.html", data)
 		return
 	}
 
-	outcomeID, err := uuid.Parse(form.OutcomeID)
+	m, err = app.marketService.Get(marketID)
 	if err != nil {
 		app.serverError(w, err)
 		return
 	}
 
-	userID, err := app.getUserId(r)
+	if m.Status == "proposed" {
+		view := viewmodels.NewMarketView(m, app.location)
+		app.sessionManager.Put(r.Context(), "flash", fmt.Sprintf("Thanks for resolving the market, payouts are made on %s unless a bettor disputes it", view.FinalizesAt))
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	if m.Status == "disputed" {
+		app.sessionManager.Put(r.Context(), "flash", "Thanks for resolving the market, as you bet on it an admin checks the resolution before payouts are made")
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "Thanks for resolving the market")
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// resolveMarketPreviewPost shows what the resolve form would pay out without
+// resolving the market.
+func (app *application) resolveMarketPreviewPost(w http.ResponseWriter, r *http.Request) {
+	marketID, err := uuid.Parse(r.PathValue("id"))
 	if err != nil {
 		app.serverError(w, err)
 		return
 	}
 
-	err = app.marketService.ResolveMarket(marketID, userID, outcomeID)
+	var form resolveMarketForm
+	err = app.decodePostForm(r, &form)
 	if err != nil {
 		app.serverError(w, err)
 		return
 	}
 
-	app.sessionManager.Put(r.Context(), "flash", "Thanks for resolving the market")
-	http.Redirect(w, r, "/", http.StatusSeeOther)
+	m, err := app.marketService.Get(marketID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	shares, ok := app.resolutionShares(w, &form, m)
+	if !ok {
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data.Market = viewmodels.NewMarketView(m, app.location)
+
+	if form.Valid() {
+		preview, err := app.marketService.PreviewResolution(marketID, userID, shares)
+		if errors.Is(err, services.ErrInvalidShares) {
+			form.AddFieldError("shares", err.Error())
+		} else if errors.Is(err, models.ErrUserNotAuthorized) {
+			app.clientError(w, http.StatusForbidden)
+			return
+		} else if err != nil {
+			app.serverError(w, err)
+			return
+		} else {
+			view := viewmodels.NewPayoutPreviewView(preview, data.Market.Outcomes)
+			data.PayoutPreview = &view
+		}
+	}
+
+	data.Form = form
+
+	if !form.Valid() {
+		app.render(w, http.StatusUnprocessableEntity, "resolve_market.html", data)
+		return
+	}
+
+	app.render(w, http.StatusOK, "resolve_market.html", data)
 }
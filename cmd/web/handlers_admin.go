@@ -0,0 +1,425 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"foresee/internal/models"
+	"foresee/internal/services"
+	"foresee/internal/validator"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type createSeasonForm struct {
+	Name                string `form:"name"`
+	StartsAt            string `form:"starts_at"`
+	EndsAt              string `form:"ends_at"`
+	StartingBalance     int    `form:"starting_balance"`
+	validator.Validator `form:"-"`
+}
+
+type rewardScheduleForm struct {
+	BaseAmount          int `form:"base_amount"`
+	StreakStepBps       int `form:"streak_step_bps"`
+	MaxAmount           int `form:"max_amount"`
+	GraceDays           int `form:"grace_days"`
+	validator.Validator `form:"-"`
+}
+
+func (app *application) adminDashboard(w http.ResponseWriter, r *http.Request) {
+	data := app.newTemplateData(r)
+	app.render(w, http.StatusOK, "admin.html", data)
+}
+
+func (app *application) adminSeasons(w http.ResponseWriter, r *http.Request) {
+	seasons, err := app.seasonService.All()
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data.Seasons = seasons
+	data.Form = createSeasonForm{StartingBalance: 1000}
+	app.render(w, http.StatusOK, "admin_seasons.html", data)
+}
+
+func (app *application) adminSeasonsPost(w http.ResponseWriter, r *http.Request) {
+	var form createSeasonForm
+	err := app.decodePostForm(r, &form)
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	form.CheckField(validator.NotBlank(form.Name), "name", "The season must have a name")
+	form.CheckField(validator.IsValidDate(form.StartsAt), "startsAt", "The start date must be valid and must not be in the past")
+	form.CheckField(validator.IsValidDate(form.EndsAt), "endsAt", "The end date must be valid and must not be in the past")
+	form.CheckField(validator.MinNumber(form.StartingBalance, 0), "startingBalance", "The starting balance cannot be negative")
+
+	var startsAt, endsAt time.Time
+	if form.Valid() {
+		startsAt, _ = time.ParseInLocation("2006-01-02T15:04", form.StartsAt, app.location)
+		endsAt, _ = time.ParseInLocation("2006-01-02T15:04", form.EndsAt, app.location)
+		form.CheckField(endsAt.After(startsAt), "endsAt", "The season must end after it starts")
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	if form.Valid() {
+		err = app.seasonService.Create(form.Name, startsAt, endsAt, form.StartingBalance, userID)
+		if errors.Is(err, services.ErrSeasonOverlaps) {
+			form.AddNonFieldError(err.Error())
+		} else if err != nil {
+			app.serverError(w, err)
+			return
+		}
+	}
+
+	if !form.Valid() {
+		seasons, err := app.seasonService.All()
+		if err != nil {
+			app.serverError(w, err)
+			return
+		}
+
+		data := app.newTemplateData(r)
+		data.Seasons = seasons
+		data.Form = form
+		app.render(w, http.StatusUnprocessableEntity, "admin_seasons.html", data)
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "Season scheduled")
+	http.Redirect(w, r, "/admin/seasons", http.StatusSeeOther)
+}
+
+func (app *application) adminRewards(w http.ResponseWriter, r *http.Request) {
+	schedule, err := app.userService.RewardSchedule()
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data.Form = rewardScheduleForm{
+		BaseAmount:    schedule.BaseAmount,
+		StreakStepBps: schedule.StreakStepBps,
+		MaxAmount:     schedule.MaxAmount,
+		GraceDays:     schedule.GraceDays,
+	}
+	app.render(w, http.StatusOK, "admin_rewards.html", data)
+}
+
+func (app *application) adminRewardsPost(w http.ResponseWriter, r *http.Request) {
+	var form rewardScheduleForm
+	err := app.decodePostForm(r, &form)
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	form.CheckField(validator.MinNumber(form.BaseAmount, 1), "baseAmount", "The base amount must be at least 1")
+	form.CheckField(validator.MinNumber(form.StreakStepBps, 0), "streakStepBps", "The streak bonus cannot be negative")
+	form.CheckField(form.MaxAmount == 0 || form.MaxAmount >= form.BaseAmount, "maxAmount", "The cap must be 0 (no cap) or at least the base amount")
+	form.CheckField(validator.MinNumber(form.GraceDays, 0), "graceDays", "Grace days cannot be negative")
+
+	if !form.Valid() {
+		data := app.newTemplateData(r)
+		data.Form = form
+		app.render(w, http.StatusUnprocessableEntity, "admin_rewards.html", data)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	err = app.userService.UpdateRewardSchedule(models.RewardSchedule{
+		BaseAmount:    form.BaseAmount,
+		StreakStepBps: form.StreakStepBps,
+		MaxAmount:     form.MaxAmount,
+		GraceDays:     form.GraceDays,
+	}, userID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "Reward schedule updated")
+	http.Redirect(w, r, "/admin/rewards", http.StatusSeeOther)
+}
+
+type feeScheduleForm struct {
+	PlatformFeeBps      int `form:"platform_fee_bps"`
+	CreatorFeeBps       int `form:"creator_fee_bps"`
+	validator.Validator `form:"-"`
+}
+
+func (app *application) adminFees(w http.ResponseWriter, r *http.Request) {
+	schedule, err := app.fees.Schedule()
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data.Form = feeScheduleForm{
+		PlatformFeeBps: schedule.PlatformFeeBps,
+		CreatorFeeBps:  schedule.CreatorFeeBps,
+	}
+
+	err = app.feeLedger(data)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	app.render(w, http.StatusOK, "admin_fees.html", data)
+}
+
+func (app *application) adminFeesPost(w http.ResponseWriter, r *http.Request) {
+	var form feeScheduleForm
+	err := app.decodePostForm(r, &form)
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	form.CheckField(form.PlatformFeeBps >= 0 && form.PlatformFeeBps <= models.MaxFeeBps, "platformFeeBps", fmt.Sprintf("The platform fee must be between 0 and %d basis points", models.MaxFeeBps))
+	form.CheckField(form.CreatorFeeBps >= 0 && form.CreatorFeeBps <= models.MaxFeeBps, "creatorFeeBps", fmt.Sprintf("The creator fee must be between 0 and %d basis points", models.MaxFeeBps))
+
+	if !form.Valid() {
+		data := app.newTemplateData(r)
+		data.Form = form
+
+		err = app.feeLedger(data)
+		if err != nil {
+			app.serverError(w, err)
+			return
+		}
+
+		app.render(w, http.StatusUnprocessableEntity, "admin_fees.html", data)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	err = app.fees.UpdateSchedule(models.FeeSchedule{
+		PlatformFeeBps: form.PlatformFeeBps,
+		CreatorFeeBps:  form.CreatorFeeBps,
+	}, userID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "Fee schedule updated, it applies to markets created from now on")
+	http.Redirect(w, r, "/admin/fees", http.StatusSeeOther)
+}
+
+// feeLedger adds the treasury balance and the latest fee entries shown
+// under the fee schedule form.
+func (app *application) feeLedger(data *templateData) error {
+	treasury, err := app.fees.Treasury()
+	if err != nil {
+		return err
+	}
+
+	entries, err := app.fees.Latest()
+	if err != nil {
+		return err
+	}
+
+	data.Treasury = treasury
+	data.FeeEntries = entries
+	return nil
+}
+
+type integrityForm struct {
+	Rule                string `form:"rule"`
+	validator.Validator `form:"-"`
+}
+
+func (app *application) adminIntegrity(w http.ResponseWriter, r *http.Request) {
+	rule, err := app.integrity.Rule()
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data.Form = integrityForm{Rule: string(rule)}
+	data.IntegrityRules = models.AllIntegrityRules()
+	app.render(w, http.StatusOK, "admin_integrity.html", data)
+}
+
+func (app *application) adminIntegrityPost(w http.ResponseWriter, r *http.Request) {
+	var form integrityForm
+	err := app.decodePostForm(r, &form)
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	form.CheckField(validator.PermittedValue(models.IntegrityRule(form.Rule), models.AllIntegrityRules()...), "rule", "Pick one of the rules")
+
+	if !form.Valid() {
+		data := app.newTemplateData(r)
+		data.Form = form
+		data.IntegrityRules = models.AllIntegrityRules()
+		app.render(w, http.StatusUnprocessableEntity, "admin_integrity.html", data)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	err = app.integrity.UpdateRule(models.IntegrityRule(form.Rule), userID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "Integrity rule updated, it applies to markets created from now on")
+	http.Redirect(w, r, "/admin/integrity", http.StatusSeeOther)
+}
+
+// correctionForm names the outcome a market is corrected to, or its Value,
+// or Date for date markets, when it is answered with a number.
+type correctionForm struct {
+	OutcomeID string   `form:"outcome_id"`
+	Value     *float64 `form:"value"`
+	Date      string   `form:"date"`
+	Reason    string   `form:"reason"`
+}
+
+// rulingValue reads the answer an admin gave to a market resolved with a
+// value, taking it from date for date markets.
+func rulingValue(value *float64, date string) (*float64, error) {
+	if date == "" {
+		return value, nil
+	}
+
+	v, err := services.DateValue(date)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
+
+func (app *application) adminCorrectionPost(w http.ResponseWriter, r *http.Request) {
+	marketID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var form correctionForm
+	err = app.decodePostForm(r, &form)
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	var outcomeID uuid.UUID
+	if form.OutcomeID != "" {
+		outcomeID, err = uuid.Parse(form.OutcomeID)
+		if err != nil {
+			app.clientError(w, http.StatusBadRequest)
+			return
+		}
+	}
+
+	adminID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	redirectTo := fmt.Sprintf("/markets/%s#corrections", marketID)
+
+	value, err := rulingValue(form.Value, form.Date)
+	if err == nil {
+		err = app.marketService.Correct(marketID, adminID, outcomeID, value, form.Reason)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMarketNotResolved),
+			errors.Is(err, services.ErrCorrectionSameOutcome),
+			errors.Is(err, services.ErrCorrectionSameValue),
+			errors.Is(err, services.ErrCorrectionConditions),
+			errors.Is(err, services.ErrValueRequired),
+			errors.Is(err, services.ErrNotValueMarket),
+			errors.Is(err, services.ErrInvalidValue),
+			errors.Is(err, services.ErrBucketDate),
+			errors.Is(err, services.ErrCorrectionReason),
+			errors.Is(err, models.ErrOutcomeDoesNotBelongToMarket):
+			app.sessionManager.Put(r.Context(), "flash_error", err.Error())
+			http.Redirect(w, r, redirectTo, http.StatusSeeOther)
+		default:
+			app.serverError(w, err)
+		}
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "The resolution has been corrected and payouts settled again")
+	http.Redirect(w, r, redirectTo, http.StatusSeeOther)
+}
+
+func (app *application) adminOracles(w http.ResponseWriter, r *http.Request) {
+	failed, err := app.oracles.Failed()
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	for i := range failed {
+		if failed[i].FailedAt != nil {
+			failedAt := failed[i].FailedAt.In(app.location)
+			failed[i].FailedAt = &failedAt
+		}
+	}
+
+	data := app.newTemplateData(r)
+	data.FailedOracles = failed
+	app.render(w, http.StatusOK, "admin_oracles.html", data)
+}
+
+func (app *application) adminOracleRetryPost(w http.ResponseWriter, r *http.Request) {
+	marketID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	err = app.oracles.Retry(marketID)
+	if err != nil {
+		if errors.Is(err, models.ErrNoRecord) {
+			http.NotFound(w, r)
+			return
+		}
+
+		app.serverError(w, err)
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "The oracle will read its data source again within a minute")
+	http.Redirect(w, r, "/admin/oracles", http.StatusSeeOther)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"foresee/internal/models"
+	"foresee/internal/services"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type commentForm struct {
+	Body     string `form:"body"`
+	ParentID string `form:"parent_id"`
+}
+
+func (app *application) createCommentPost(w http.ResponseWriter, r *http.Request) {
+	marketID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var form commentForm
+	err = app.decodePostForm(r, &form)
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	var parentID *uuid.UUID
+	if form.ParentID != "" {
+		id, err := uuid.Parse(form.ParentID)
+		if err != nil {
+			app.clientError(w, http.StatusBadRequest)
+			return
+		}
+		parentID = &id
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	redirectTo := fmt.Sprintf("/markets/%s#comments", marketID)
+
+	id, err := app.comments.Post(marketID, userID, parentID, form.Body)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrNoRecord), errors.Is(err, services.ErrNotGroupMember):
+			http.NotFound(w, r)
+		case errors.Is(err, services.ErrCommentEmpty), errors.Is(err, services.ErrCommentTooLong), errors.Is(err, services.ErrCommentNotFound), errors.Is(err, services.ErrUserSuspended):
+			app.sessionManager.Put(r.Context(), "flash_error", err.Error())
+			http.Redirect(w, r, redirectTo, http.StatusSeeOther)
+		default:
+			app.serverError(w, err)
+		}
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("/markets/%s#comment-%s", marketID, id), http.StatusSeeOther)
+}
+
+func (app *application) editCommentPost(w http.ResponseWriter, r *http.Request) {
+	commentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var form commentForm
+	err = app.decodePostForm(r, &form)
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	c, err := app.comments.Edit(commentID, userID, form.Body)
+	app.commentActionDone(w, r, c, err, "Your comment has been updated")
+}
+
+func (app *application) deleteCommentPost(w http.ResponseWriter, r *http.Request) {
+	commentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	c, err := app.comments.Delete(commentID, userID)
+	app.commentActionDone(w, r, c, err, "Your comment has been deleted")
+}
+
+// commentActionDone maps the errors shared by the comment actions and sends
+// the user back to the comment on success.
+func (app *application) commentActionDone(w http.ResponseWriter, r *http.Request, c models.Comment, err error, message string) {
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrCommentNotFound):
+			http.NotFound(w, r)
+		case errors.Is(err, services.ErrCommentNotAuthor):
+			app.clientError(w, http.StatusForbidden)
+		case errors.Is(err, services.ErrCommentEmpty), errors.Is(err, services.ErrCommentTooLong), errors.Is(err, services.ErrCommentDeleted):
+			app.sessionManager.Put(r.Context(), "flash_error", err.Error())
+			http.Redirect(w, r, fmt.Sprintf("/markets/%s#comment-%s", c.MarketID, c.ID), http.StatusSeeOther)
+		default:
+			app.serverError(w, err)
+		}
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", message)
+	http.Redirect(w, r, fmt.Sprintf("/markets/%s#comment-%s", c.MarketID, c.ID), http.StatusSeeOther)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"foresee/internal/models"
+	"foresee/internal/services"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type disputeForm struct {
+	Justification string `form:"justification"`
+}
+
+// disputeDecisionForm overturns a resolution to OutcomeID, or to Value or
+// Date for markets answered with a number, see correctionForm.
+type disputeDecisionForm struct {
+	Decision  string   `form:"decision"`
+	OutcomeID string   `form:"outcome_id"`
+	Value     *float64 `form:"value"`
+	Date      string   `form:"date"`
+}
+
+func (app *application) disputeMarketPost(w http.ResponseWriter, r *http.Request) {
+	marketID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var form disputeForm
+	err = app.decodePostForm(r, &form)
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	redirectTo := fmt.Sprintf("/markets/%s#disputes", marketID)
+
+	err = app.disputes.File(marketID, userID, form.Justification)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrDisputeJustification),
+			errors.Is(err, services.ErrDisputeNotBettor),
+			errors.Is(err, services.ErrDisputeWindowClosed),
+			errors.Is(err, models.ErrDisputeAlreadyFiled):
+			app.sessionManager.Put(r.Context(), "flash_error", err.Error())
+			http.Redirect(w, r, redirectTo, http.StatusSeeOther)
+		default:
+			app.serverError(w, err)
+		}
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "Your dispute has been filed, an admin will review the resolution")
+	http.Redirect(w, r, redirectTo, http.StatusSeeOther)
+}
+
+func (app *application) adminDisputes(w http.ResponseWriter, r *http.Request) {
+	adminID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	cases, err := app.disputes.Queue(adminID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data.DisputeCases = cases
+	app.render(w, http.StatusOK, "admin_disputes.html", data)
+}
+
+func (app *application) adminDisputePost(w http.ResponseWriter, r *http.Request) {
+	marketID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var form disputeDecisionForm
+	err = app.decodePostForm(r, &form)
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	var outcomeID uuid.UUID
+	if form.OutcomeID != "" {
+		outcomeID, err = uuid.Parse(form.OutcomeID)
+		if err != nil {
+			app.clientError(w, http.StatusBadRequest)
+			return
+		}
+	}
+
+	adminID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	value, err := rulingValue(form.Value, form.Date)
+	if err == nil {
+		err = app.disputes.Decide(marketID, adminID, models.DisputeStatus(form.Decision), outcomeID, value)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrMarketNotDisputed),
+			errors.Is(err, services.ErrInvalidDisputeDecision),
+			errors.Is(err, services.ErrValueRequired),
+			errors.Is(err, services.ErrNotValueMarket),
+			errors.Is(err, services.ErrInvalidValue),
+			errors.Is(err, services.ErrBucketDate),
+			errors.Is(err, services.ErrConflictOfInterest),
+			errors.Is(err, models.ErrOutcomeDoesNotBelongToMarket):
+			app.sessionManager.Put(r.Context(), "flash_error", err.Error())
+			http.Redirect(w, r, "/admin/disputes", http.StatusSeeOther)
+		default:
+			app.serverError(w, err)
+		}
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "The market has been settled")
+	http.Redirect(w, r, "/admin/disputes", http.StatusSeeOther)
+}
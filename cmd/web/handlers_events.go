@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"foresee/cmd/web/viewmodels"
+	"foresee/internal/models"
+	"foresee/internal/services"
+	"foresee/internal/validator"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type createEventForm struct {
+	Title               string `form:"title"`
+	Description         string `form:"description"`
+	Date                string `form:"date"`
+	validator.Validator `form:"-"`
+}
+
+func (app *application) events(w http.ResponseWriter, r *http.Request) {
+	app.renderEvents(w, r, http.StatusOK, createEventForm{})
+}
+
+func (app *application) renderEvents(w http.ResponseWriter, r *http.Request, status int, form createEventForm) {
+	upcoming, err := app.eventPages.Upcoming()
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	past, err := app.eventPages.Past()
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data.Events = upcoming
+	data.PastEvents = past
+	data.Form = form
+	app.render(w, status, "events.html", data)
+}
+
+func (app *application) eventsPost(w http.ResponseWriter, r *http.Request) {
+	var form createEventForm
+	err := app.decodePostForm(r, &form)
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	form.CheckField(validator.NotBlank(form.Title), "title", "The event must have a title")
+	form.CheckField(validator.MaxChars(form.Title, 120), "title", "The title cannot be longer than 120 characters")
+	form.CheckField(validator.NotBlank(form.Date), "date", "The event must have a date")
+
+	var id uuid.UUID
+	if form.Valid() {
+		id, err = app.eventPages.Create(form.Title, form.Description, form.Date, userID)
+		if errors.Is(err, services.ErrEventDate) {
+			form.AddFieldError("date", err.Error())
+		} else if errors.Is(err, services.ErrUserSuspended) {
+			form.AddNonFieldError(err.Error())
+		} else if err != nil {
+			app.serverError(w, err)
+			return
+		}
+	}
+
+	if !form.Valid() {
+		app.renderEvents(w, r, http.StatusUnprocessableEntity, form)
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "Your event has been created, add its markets below")
+	http.Redirect(w, r, fmt.Sprintf("/events/%s", id), http.StatusSeeOther)
+}
+
+func (app *application) viewEvent(w http.ResponseWriter, r *http.Request) {
+	eventID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	page, err := app.eventPages.Get(eventID, app.viewerID(r))
+	if err != nil {
+		if errors.Is(err, models.ErrNoRecord) {
+			http.NotFound(w, r)
+			return
+		}
+
+		app.serverError(w, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data.Event = page
+	data.EventMarkets = viewmodels.NewEventView(page.Markets, app.location)
+	app.render(w, http.StatusOK, "event.html", data)
+}
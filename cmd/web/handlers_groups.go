@@ -0,0 +1,318 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"foresee/cmd/web/viewmodels"
+	"foresee/internal/models"
+	"foresee/internal/services"
+	"foresee/internal/validator"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type createGroupForm struct {
+	Name                string `form:"name"`
+	Description         string `form:"description"`
+	validator.Validator `form:"-"`
+}
+
+type createInviteForm struct {
+	ValidForHours       int `form:"valid_for_hours"`
+	MaxUses             int `form:"max_uses"`
+	validator.Validator `form:"-"`
+}
+
+type groupRoleForm struct {
+	Role                string `form:"role"`
+	validator.Validator `form:"-"`
+}
+
+func (app *application) groups(w http.ResponseWriter, r *http.Request) {
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	groups, err := app.groupService.ForUser(userID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data.Groups = groups
+	data.Form = createGroupForm{}
+	app.render(w, http.StatusOK, "groups.html", data)
+}
+
+func (app *application) groupsPost(w http.ResponseWriter, r *http.Request) {
+	var form createGroupForm
+	err := app.decodePostForm(r, &form)
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	form.CheckField(validator.NotBlank(form.Name), "name", "The group must have a name")
+	form.CheckField(validator.MaxChars(form.Name, 60), "name", "The name cannot be longer than 60 characters")
+
+	if !form.Valid() {
+		groups, err := app.groupService.ForUser(userID)
+		if err != nil {
+			app.serverError(w, err)
+			return
+		}
+
+		data := app.newTemplateData(r)
+		data.Groups = groups
+		data.Form = form
+		app.render(w, http.StatusUnprocessableEntity, "groups.html", data)
+		return
+	}
+
+	id, err := app.groupService.Create(form.Name, form.Description, userID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "Your group has been created, invite people with a link below")
+	http.Redirect(w, r, fmt.Sprintf("/groups/%s", id), http.StatusSeeOther)
+}
+
+func (app *application) viewGroup(w http.ResponseWriter, r *http.Request) {
+	groupID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	page, err := app.groupService.Get(groupID, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrNotGroupMember) || errors.Is(err, models.ErrNoRecord) {
+			http.NotFound(w, r)
+			return
+		}
+
+		app.serverError(w, err)
+		return
+	}
+
+	marketViews := make([]viewmodels.MarketView, 0, len(page.Markets))
+	for _, m := range page.Markets {
+		marketViews = append(marketViews, viewmodels.NewMarketView(*m, app.location))
+	}
+
+	data := app.newTemplateData(r)
+	data.Group = page
+	data.Markets = marketViews
+	data.GroupRoles = []models.GroupRole{models.GroupAdmin, models.GroupMember}
+	data.Form = createInviteForm{ValidForHours: 72, MaxUses: 1}
+	app.render(w, http.StatusOK, "group.html", data)
+}
+
+func (app *application) groupInvitesPost(w http.ResponseWriter, r *http.Request) {
+	groupID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var form createInviteForm
+	err = app.decodePostForm(r, &form)
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	form.CheckField(validator.MinNumber(form.ValidForHours, 1), "validForHours", "Invites must be valid for at least an hour")
+	form.CheckField(form.ValidForHours <= 24*30, "validForHours", "Invites cannot be valid for more than 30 days")
+	form.CheckField(validator.MinNumber(form.MaxUses, 0), "maxUses", "The number of uses cannot be negative")
+
+	redirectTo := fmt.Sprintf("/groups/%s", groupID)
+
+	if !form.Valid() {
+		for _, msg := range form.FieldErrors {
+			app.sessionManager.Put(r.Context(), "flash_error", msg)
+			break
+		}
+		http.Redirect(w, r, redirectTo, http.StatusSeeOther)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	// Zero uses means the link can be shared with anyone until it expires.
+	var maxUses *int
+	if form.MaxUses > 0 {
+		maxUses = &form.MaxUses
+	}
+
+	_, err = app.groupService.CreateInvite(groupID, userID, time.Duration(form.ValidForHours)*time.Hour, maxUses)
+	if err != nil {
+		if errors.Is(err, services.ErrNotGroupMember) {
+			http.NotFound(w, r)
+			return
+		}
+		if errors.Is(err, services.ErrGroupPermission) {
+			app.clientError(w, http.StatusForbidden)
+			return
+		}
+
+		app.serverError(w, err)
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "A new invite link has been created")
+	http.Redirect(w, r, redirectTo, http.StatusSeeOther)
+}
+
+func (app *application) groupInviteRevokePost(w http.ResponseWriter, r *http.Request) {
+	groupID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	inviteID, err := uuid.Parse(r.PathValue("inviteID"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	err = app.groupService.RevokeInvite(groupID, inviteID, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrNotGroupMember) {
+			http.NotFound(w, r)
+			return
+		}
+		if errors.Is(err, services.ErrGroupPermission) {
+			app.clientError(w, http.StatusForbidden)
+			return
+		}
+
+		app.serverError(w, err)
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "The invite link has been revoked")
+	http.Redirect(w, r, fmt.Sprintf("/groups/%s", groupID), http.StatusSeeOther)
+}
+
+func (app *application) groupMemberRolePost(w http.ResponseWriter, r *http.Request) {
+	groupID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	memberID, err := uuid.Parse(r.PathValue("userID"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var form groupRoleForm
+	err = app.decodePostForm(r, &form)
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	if !validator.PermittedValue(models.GroupRole(form.Role), models.AllGroupRoles()...) {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	redirectTo := fmt.Sprintf("/groups/%s", groupID)
+
+	err = app.groupService.SetRole(groupID, userID, memberID, models.GroupRole(form.Role))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotGroupMember), errors.Is(err, models.ErrNoRecord):
+			http.NotFound(w, r)
+		case errors.Is(err, services.ErrGroupPermission), errors.Is(err, services.ErrInvalidGroupRole):
+			app.sessionManager.Put(r.Context(), "flash_error", err.Error())
+			http.Redirect(w, r, redirectTo, http.StatusSeeOther)
+		default:
+			app.serverError(w, err)
+		}
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "The member's role has been updated")
+	http.Redirect(w, r, redirectTo, http.StatusSeeOther)
+}
+
+func (app *application) viewInvite(w http.ResponseWriter, r *http.Request) {
+	invite, err := app.groupService.Invite(r.PathValue("token"))
+	if err != nil {
+		if errors.Is(err, services.ErrInviteInvalid) {
+			app.sessionManager.Put(r.Context(), "flash_error", err.Error())
+			http.Redirect(w, r, "/groups", http.StatusSeeOther)
+			return
+		}
+
+		app.serverError(w, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data.Invite = invite
+	app.render(w, http.StatusOK, "invite.html", data)
+}
+
+func (app *application) joinGroupPost(w http.ResponseWriter, r *http.Request) {
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	groupID, err := app.groupService.Join(r.PathValue("token"), userID)
+	if err != nil {
+		if errors.Is(err, services.ErrInviteInvalid) {
+			app.sessionManager.Put(r.Context(), "flash_error", err.Error())
+			http.Redirect(w, r, "/groups", http.StatusSeeOther)
+			return
+		}
+
+		app.serverError(w, err)
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "Welcome to the group!")
+	http.Redirect(w, r, fmt.Sprintf("/groups/%s", groupID), http.StatusSeeOther)
+}
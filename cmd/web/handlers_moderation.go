@@ -0,0 +1,167 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"foresee/internal/models"
+	"foresee/internal/services"
+	"foresee/internal/validator"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type reportForm struct {
+	Reason  string `form:"reason"`
+	Details string `form:"details"`
+}
+
+type moderationActionForm struct {
+	TargetType string `form:"target_type"`
+	TargetID   string `form:"target_id"`
+	Action     string `form:"action"`
+	Note       string `form:"note"`
+}
+
+func (app *application) reportMarketPost(w http.ResponseWriter, r *http.Request) {
+	app.report(w, r, models.ReportMarket)
+}
+
+func (app *application) reportCommentPost(w http.ResponseWriter, r *http.Request) {
+	app.report(w, r, models.ReportComment)
+}
+
+func (app *application) report(w http.ResponseWriter, r *http.Request, targetType models.ReportTarget) {
+	targetID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var form reportForm
+	err = app.decodePostForm(r, &form)
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	if !validator.PermittedValue(models.ReportReason(form.Reason), models.AllReportReasons()...) {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	if !validator.MaxChars(form.Details, 1000) {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	marketID, err := app.moderation.Report(userID, targetType, targetID, models.ReportReason(form.Reason), form.Details)
+	if err != nil {
+		if errors.Is(err, models.ErrNoRecord) {
+			http.NotFound(w, r)
+			return
+		}
+
+		app.serverError(w, err)
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "Thanks, a moderator will review your report")
+	http.Redirect(w, r, fmt.Sprintf("/markets/%s", marketID), http.StatusSeeOther)
+}
+
+func (app *application) moderationQueue(w http.ResponseWriter, r *http.Request) {
+	items, err := app.moderation.Queue()
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	log, err := app.moderation.RecentActions(50)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data.ReportedItems = items
+	data.ModerationLog = log
+	app.render(w, http.StatusOK, "moderation.html", data)
+}
+
+func (app *application) moderationActionPost(w http.ResponseWriter, r *http.Request) {
+	var form moderationActionForm
+	err := app.decodePostForm(r, &form)
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	targetType := models.ReportTarget(form.TargetType)
+	action := models.ModerationAction(form.Action)
+	targetID, err := uuid.Parse(form.TargetID)
+	if err != nil ||
+		!validator.PermittedValue(targetType, models.ReportMarket, models.ReportComment) ||
+		!validator.PermittedValue(action, models.AllModerationActions()...) {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	app.moderate(w, r, userID, targetType, targetID, action, form.Note, "/moderation")
+}
+
+// removeCommentPost lets moderators remove a comment straight from the
+// discussion. It is recorded in the audit trail like a queue action.
+func (app *application) removeCommentPost(w http.ResponseWriter, r *http.Request) {
+	commentID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	redirectTo := r.Header.Get("Referer")
+	if redirectTo == "" {
+		redirectTo = "/"
+	}
+
+	app.moderate(w, r, userID, models.ReportComment, commentID, models.ActionHide, "", redirectTo)
+}
+
+func (app *application) moderate(w http.ResponseWriter, r *http.Request, userID uuid.UUID, targetType models.ReportTarget, targetID uuid.UUID, action models.ModerationAction, note, redirectTo string) {
+	err := app.moderation.Act(userID, targetType, targetID, action, note)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrUserNotAuthorized):
+			app.clientError(w, http.StatusForbidden)
+		case errors.Is(err, models.ErrNoRecord):
+			http.NotFound(w, r)
+		case errors.Is(err, services.ErrInvalidModerationAction), errors.Is(err, models.ErrMarketAlreadyResolved):
+			app.sessionManager.Put(r.Context(), "flash_error", err.Error())
+			http.Redirect(w, r, redirectTo, http.StatusSeeOther)
+		default:
+			app.serverError(w, err)
+		}
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", fmt.Sprintf("Moderation action %q applied", action))
+	http.Redirect(w, r, redirectTo, http.StatusSeeOther)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"net/http"
+)
+
+// notificationsPage lists the user's notifications and marks them read.
+func (app *application) notificationsPage(w http.ResponseWriter, r *http.Request) {
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	notifications, err := app.notifications.ForUser(userID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	err = app.notifications.MarkAllRead(userID)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	for i := range notifications {
+		notifications[i].CreatedAt = notifications[i].CreatedAt.In(app.location)
+	}
+
+	data := app.newTemplateData(r)
+	data.Notifications = notifications
+	app.render(w, http.StatusOK, "notifications.html", data)
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"foresee/internal/oidc"
+	"foresee/internal/services"
+	"net/http"
+)
+
+func (app *application) oidcLogin(w http.ResponseWriter, r *http.Request) {
+	if app.oidc == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	state, err := oidc.RandomString()
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	verifier, err := oidc.RandomString()
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "oidcState", state)
+	app.sessionManager.Put(r.Context(), "oidcNonce", nonce)
+	app.sessionManager.Put(r.Context(), "oidcVerifier", verifier)
+
+	http.Redirect(w, r, app.oidc.AuthCodeURL(state, nonce, verifier), http.StatusFound)
+}
+
+func (app *application) oidcCallback(w http.ResponseWriter, r *http.Request) {
+	if app.oidc == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	state := app.sessionManager.PopString(r.Context(), "oidcState")
+	nonce := app.sessionManager.PopString(r.Context(), "oidcNonce")
+	verifier := app.sessionManager.PopString(r.Context(), "oidcVerifier")
+
+	query := r.URL.Query()
+	if query.Get("error") != "" {
+		app.sessionManager.Put(r.Context(), "flash_error", "Single sign-on was cancelled or denied")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	if state == "" || query.Get("state") != state || query.Get("code") == "" {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	claims, err := app.oidc.Exchange(r.Context(), query.Get("code"), verifier, nonce)
+	if err != nil {
+		app.errorLog.Print(err)
+		app.sessionManager.Put(r.Context(), "flash_error", "We could not verify your identity, please try again")
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	id, err := app.userService.LoginWithIdentity(services.ExternalIdentity{
+		Issuer:            claims.Issuer,
+		Subject:           claims.Subject,
+		Email:             claims.Email,
+		EmailVerified:     bool(claims.EmailVerified),
+		PreferredUsername: claims.PreferredUsername,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrIdentityEmailMissing) ||
+			errors.Is(err, services.ErrIdentityEmailNotVerified) ||
+			errors.Is(err, services.ErrIdentityEmailUnverified) ||
+			errors.Is(err, services.ErrIdentityConcurrentLogin) {
+			app.sessionManager.Put(r.Context(), "flash_error", err.Error())
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		app.serverError(w, err)
+		return
+	}
+
+	err = app.sessionManager.RenewToken(r.Context())
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "authenticatedUserID", id.String())
+
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
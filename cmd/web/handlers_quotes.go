@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"foresee/internal/models"
+	"foresee/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// marketQuote answers the bet modal's live preview with what a bet of
+// ?amount= on ?outcome_id= would pay if that outcome won.
+func (app *application) marketQuote(w http.ResponseWriter, r *http.Request) {
+	marketID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	outcomeID, err := uuid.Parse(r.URL.Query().Get("outcome_id"))
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	amount, err := strconv.Atoi(r.URL.Query().Get("amount"))
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	m, err := app.marketService.Get(marketID)
+	if err != nil {
+		if errors.Is(err, models.ErrNoRecord) {
+			http.NotFound(w, r)
+			return
+		}
+
+		app.serverError(w, err)
+		return
+	}
+
+	allowed, err := app.marketService.CanAccess(m, app.viewerID(r))
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	if !allowed {
+		http.NotFound(w, r)
+		return
+	}
+
+	if m.Status != "open" {
+		app.clientError(w, http.StatusConflict)
+		return
+	}
+
+	quote, err := app.marketService.Quote(m, outcomeID, amount)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrQuoteAmount), errors.Is(err, services.ErrQuoteTooLarge),
+			errors.Is(err, models.ErrOutcomeDoesNotBelongToMarket):
+			app.writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			app.serverError(w, err)
+		}
+		return
+	}
+
+	app.writeJSON(w, http.StatusOK, quote)
+}
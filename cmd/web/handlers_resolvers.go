@@ -0,0 +1,99 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"foresee/cmd/web/viewmodels"
+	"foresee/internal/models"
+	"foresee/internal/services"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type resolverAnswerForm struct {
+	Decision string `form:"decision"`
+}
+
+// resolverRequest shows a designated resolver the market they were asked
+// to resolve, to accept or decline.
+func (app *application) resolverRequest(w http.ResponseWriter, r *http.Request) {
+	marketID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	m, err := app.marketService.Get(marketID)
+	if err != nil {
+		if errors.Is(err, models.ErrNoRecord) {
+			http.NotFound(w, r)
+			return
+		}
+
+		app.serverError(w, err)
+		return
+	}
+
+	if !services.IsDesignatedResolver(m, userID) {
+		http.NotFound(w, r)
+		return
+	}
+
+	data := app.newTemplateData(r)
+	data.Market = viewmodels.NewMarketView(m, app.location)
+	app.render(w, http.StatusOK, "resolver.html", data)
+}
+
+func (app *application) resolverRequestPost(w http.ResponseWriter, r *http.Request) {
+	marketID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	var form resolverAnswerForm
+	err = app.decodePostForm(r, &form)
+	if err != nil || (form.Decision != "accept" && form.Decision != "decline") {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	accept := form.Decision == "accept"
+
+	err = app.marketService.AnswerDesignation(marketID, userID, accept)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrNoRecord), errors.Is(err, models.ErrUserNotAuthorized):
+			http.NotFound(w, r)
+		case errors.Is(err, services.ErrDesignationAnswered), errors.Is(err, services.ErrDesignationExpired),
+			errors.Is(err, services.ErrConditionNotMet):
+			app.sessionManager.Put(r.Context(), "flash_error", err.Error())
+			http.Redirect(w, r, fmt.Sprintf("/markets/%s/resolver", marketID), http.StatusSeeOther)
+		default:
+			app.serverError(w, err)
+		}
+		return
+	}
+
+	if !accept {
+		app.sessionManager.Put(r.Context(), "flash", "You declined to resolve the market, it has been voided")
+		http.Redirect(w, r, "/notifications", http.StatusSeeOther)
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "You are now the resolver of this market, it is open for bets")
+	http.Redirect(w, r, fmt.Sprintf("/markets/%s", marketID), http.StatusSeeOther)
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"foresee/cmd/web/viewmodels"
+	"foresee/internal/models"
+	"foresee/internal/services"
+	"foresee/internal/validator"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+type editMarketForm struct {
+	Title               string `form:"title"`
+	Description         string `form:"description"`
+	Category            string `form:"category"`
+	ExpiresAt           string `form:"expires_at"`
+	validator.Validator `form:"-"`
+}
+
+type clarifyMarketForm struct {
+	Clarification       string `form:"clarification"`
+	validator.Validator `form:"-"`
+}
+
+// editMarket shows the full edit form while nobody has bet on the market and
+// only the clarification form afterwards.
+func (app *application) editMarket(w http.ResponseWriter, r *http.Request) {
+	m, ok := app.editableMarket(w, r)
+	if !ok {
+		return
+	}
+
+	view := viewmodels.NewMarketView(m, app.location)
+
+	var form any = clarifyMarketForm{}
+	if !view.HasBets {
+		form = editMarketForm{
+			Title:       m.Title,
+			Description: m.Description,
+			Category:    string(m.Category),
+			ExpiresAt:   m.ExpiresAt.In(app.location).Format("2006-01-02T15:04"),
+		}
+	}
+
+	data := app.newTemplateData(r)
+	data.Market = view
+	data.Form = form
+	app.render(w, http.StatusOK, "edit_market.html", data)
+}
+
+func (app *application) editMarketPost(w http.ResponseWriter, r *http.Request) {
+	m, ok := app.editableMarket(w, r)
+	if !ok {
+		return
+	}
+
+	var form editMarketForm
+	err := app.decodePostForm(r, &form)
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	form.CheckField(validator.NotBlank(form.Title), "title", "Market must have a name")
+	form.CheckField(validator.MinChars(form.Title, 4), "title", "Title must be at least 4 characters long")
+	form.CheckField(validator.NotBlank(form.Description), "description", "Description cannot be empty")
+	form.CheckField(validator.PermittedValue(models.Category(form.Category), models.AllCategories()...), "category", "The category must be valid")
+	form.CheckField(validator.IsValidDate(form.ExpiresAt), "expiresAt", "The expiry date must be valid and must not be in the past")
+
+	if form.Valid() {
+		err = app.marketService.Edit(m.ID, m.CreatedBy, services.MarketEdit{
+			Title:       form.Title,
+			Description: form.Description,
+			Category:    form.Category,
+			ExpiresAt:   form.ExpiresAt,
+		})
+		if errors.Is(err, services.ErrMarketHasBets) || errors.Is(err, services.ErrMarketNotEditable) {
+			app.sessionManager.Put(r.Context(), "flash_error", err.Error())
+			http.Redirect(w, r, fmt.Sprintf("/markets/%s/edit", m.ID), http.StatusSeeOther)
+			return
+		} else if err != nil {
+			app.serverError(w, err)
+			return
+		}
+	}
+
+	if !form.Valid() {
+		data := app.newTemplateData(r)
+		data.Market = viewmodels.NewMarketView(m, app.location)
+		data.Form = form
+		app.render(w, http.StatusUnprocessableEntity, "edit_market.html", data)
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "The market has been updated")
+	http.Redirect(w, r, fmt.Sprintf("/markets/%s", m.ID), http.StatusSeeOther)
+}
+
+func (app *application) clarifyMarketPost(w http.ResponseWriter, r *http.Request) {
+	m, ok := app.editableMarket(w, r)
+	if !ok {
+		return
+	}
+
+	var form clarifyMarketForm
+	err := app.decodePostForm(r, &form)
+	if err != nil {
+		app.clientError(w, http.StatusBadRequest)
+		return
+	}
+
+	form.CheckField(validator.NotBlank(form.Clarification), "clarification", "The clarification cannot be empty")
+	form.CheckField(validator.MaxChars(form.Clarification, 1000), "clarification", "Clarifications cannot be longer than 1000 characters")
+
+	if form.Valid() {
+		err = app.marketService.Clarify(m.ID, m.CreatedBy, form.Clarification)
+		if errors.Is(err, services.ErrMarketNotEditable) {
+			app.sessionManager.Put(r.Context(), "flash_error", err.Error())
+			http.Redirect(w, r, fmt.Sprintf("/markets/%s", m.ID), http.StatusSeeOther)
+			return
+		} else if err != nil {
+			app.serverError(w, err)
+			return
+		}
+	}
+
+	if !form.Valid() {
+		data := app.newTemplateData(r)
+		data.Market = viewmodels.NewMarketView(m, app.location)
+		data.Form = form
+		app.render(w, http.StatusUnprocessableEntity, "edit_market.html", data)
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "Your clarification has been added")
+	http.Redirect(w, r, fmt.Sprintf("/markets/%s#history", m.ID), http.StatusSeeOther)
+}
+
+// editableMarket loads the market in the path and checks that the current
+// user created it. It writes the error response itself when it returns false.
+func (app *application) editableMarket(w http.ResponseWriter, r *http.Request) (models.Market, bool) {
+	marketID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return models.Market{}, false
+	}
+
+	m, err := app.marketService.Get(marketID)
+	if err != nil {
+		if errors.Is(err, models.ErrNoRecord) {
+			http.NotFound(w, r)
+		} else {
+			app.serverError(w, err)
+		}
+		return models.Market{}, false
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return models.Market{}, false
+	}
+
+	if m.CreatedBy != userID {
+		app.clientError(w, http.StatusForbidden)
+		return models.Market{}, false
+	}
+
+	if m.Status != "open" {
+		app.sessionManager.Put(r.Context(), "flash_error", services.ErrMarketNotEditable.Error())
+		http.Redirect(w, r, fmt.Sprintf("/markets/%s", m.ID), http.StatusSeeOther)
+		return models.Market{}, false
+	}
+
+	return m, true
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"foresee/cmd/web/viewmodels"
+	"foresee/internal/models"
+	"foresee/internal/services"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+func (app *application) viewSeries(w http.ResponseWriter, r *http.Request) {
+	seriesID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	page, err := app.series.Get(seriesID, app.viewerID(r))
+	if err != nil {
+		if errors.Is(err, services.ErrNotGroupMember) || errors.Is(err, models.ErrNoRecord) {
+			http.NotFound(w, r)
+			return
+		}
+
+		app.serverError(w, err)
+		return
+	}
+
+	page.NextExpiresAt = page.NextExpiresAt.In(app.location)
+	if page.StoppedAt != nil {
+		stoppedAt := page.StoppedAt.In(app.location)
+		page.StoppedAt = &stoppedAt
+	}
+
+	marketViews := make([]viewmodels.MarketView, 0, len(page.Markets))
+	for _, m := range page.Markets {
+		marketViews = append(marketViews, viewmodels.NewMarketView(*m, app.location))
+	}
+
+	data := app.newTemplateData(r)
+	data.Series = page
+	data.Markets = marketViews
+	app.render(w, http.StatusOK, "series.html", data)
+}
+
+func (app *application) stopSeriesPost(w http.ResponseWriter, r *http.Request) {
+	seriesID, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	userID, err := app.getUserId(r)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	redirectTo := fmt.Sprintf("/series/%s", seriesID)
+
+	err = app.series.Stop(seriesID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrNoRecord):
+			http.NotFound(w, r)
+		case errors.Is(err, services.ErrSeriesPermission):
+			app.sessionManager.Put(r.Context(), "flash_error", err.Error())
+			http.Redirect(w, r, redirectTo, http.StatusSeeOther)
+		default:
+			app.serverError(w, err)
+		}
+		return
+	}
+
+	app.sessionManager.Put(r.Context(), "flash", "The series is stopped, no more markets will be created for it")
+	http.Redirect(w, r, redirectTo, http.StatusSeeOther)
+}
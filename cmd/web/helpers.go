@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -49,6 +50,18 @@ func (app *application) render(w http.ResponseWriter, status int, page string, d
 	buf.WriteTo(w)
 }
 
+func (app *application) writeJSON(w http.ResponseWriter, status int, v any) {
+	js, err := json.Marshal(v)
+	if err != nil {
+		app.serverError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(js)
+}
+
 func (app *application) decodePostForm(r *http.Request, dst any) error {
 	err := r.ParseForm()
 	if err != nil {
@@ -81,3 +94,14 @@ func isAuthenticated(r *http.Request) bool {
 func (app *application) getUserId(r *http.Request) (uuid.UUID, error) {
 	return uuid.Parse(app.sessionManager.GetString(r.Context(), "authenticatedUserID"))
 }
+
+// viewerID returns the authenticated user's ID, or uuid.Nil for anonymous
+// visitors, for pages that are public but filter what they show.
+func (app *application) viewerID(r *http.Request) uuid.UUID {
+	id, err := app.getUserId(r)
+	if err != nil {
+		return uuid.Nil
+	}
+
+	return id
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// runEvery runs job straight away and then on every tick of interval in its
+// own goroutine. Failures and panics are logged so that one bad run does not
+// stop the schedule or take the web server down with it.
+func (app *application) runEvery(name string, interval time.Duration, job func() error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			app.runJob(name, job)
+			<-ticker.C
+		}
+	}()
+}
+
+func (app *application) runJob(name string, job func() error) {
+	defer func() {
+		if err := recover(); err != nil {
+			app.errorLog.Output(2, fmt.Sprintf("job %s panicked: %v", name, err))
+		}
+	}()
+
+	start := time.Now()
+	err := job()
+	if err != nil {
+		app.errorLog.Printf("job %s failed: %v", name, err)
+		return
+	}
+
+	app.infoLog.Printf("job %s finished in %s", name, time.Since(start).Round(time.Millisecond))
+}
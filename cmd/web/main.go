@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"foresee/internal/models"
+	"foresee/internal/oidc"
+	"foresee/internal/oracle"
 	"foresee/internal/services"
 	"html/template"
 	"log"
@@ -24,10 +27,26 @@ type application struct {
 	templateCache  map[string]*template.Template
 	formDecoder    *form.Decoder
 	users          *models.UserModel
+	userService    *services.UserService
 	marketService  *services.MarketService
+	groupService   *services.GroupService
+	comments       *services.CommentService
+	moderation     *services.ModerationService
+	disputes       *services.DisputeService
+	series         *services.SeriesService
+	eventPages     *services.EventService
+	oracles        *services.OracleService
+	notifications  *services.NotificationService
+	fees           *services.FeeService
+	integrity      *services.IntegrityService
 	betService     *services.BetService
+	profileService *services.ProfileService
+	leaderboard    *services.LeaderboardService
+	seasonService  *services.SeasonService
+	achievements   *services.AchievementService
 	sessionManager *scs.SessionManager
 	location       *time.Location
+	oidc           *oidc.Provider
 }
 
 func main() {
@@ -94,6 +113,10 @@ func main() {
 		}
 	}
 
+	events := &services.Events{
+		ErrorLog: errorLog,
+	}
+
 	marketModel := models.MarketModel{
 		DB: db,
 	}
@@ -106,9 +129,51 @@ func main() {
 		Outcomes: &outcomeModel,
 	}
 
+	groupModel := models.GroupModel{
+		DB: db,
+	}
+
+	feeModel := models.FeeModel{
+		DB: db,
+	}
+
+	eventModel := models.EventModel{
+		DB: db,
+	}
+
+	oracleModel := models.OracleModel{
+		DB: db,
+	}
+
+	integrityModel := models.IntegrityModel{
+		DB: db,
+	}
+
+	disputeModel := models.DisputeModel{
+		DB: db,
+	}
+
+	// Oracle endpoints are chosen by market creators, so they may only be on
+	// public addresses unless a developer opts in to reach a local server.
+	oracleClient := oracle.PublicClient(10 * time.Second)
+	if os.Getenv("ORACLE_ALLOW_PRIVATE_HOSTS") == "true" {
+		oracleClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
 	marketService := services.MarketService{
 		Markets:        &marketModel,
+		Groups:         &groupModel,
+		Revisions:      &models.RevisionModel{DB: db},
+		Corrections:    &models.CorrectionModel{DB: db},
+		Fees:           &feeModel,
+		Integrity:      &integrityModel,
+		Disputes:       &disputeModel,
+		Liquidity:      &models.LiquidityModel{DB: db},
+		MarketEvents:   &eventModel,
+		Oracles:        &oracleModel,
+		OracleSources:  oracle.Default(oracleClient, os.Getenv("ORACLE_SCRIPTS_DIR")),
 		OutcomeService: outcomeService,
+		Events:         events,
 	}
 
 	userModel := models.UserModel{
@@ -116,33 +181,155 @@ func main() {
 	}
 
 	userService := services.UserService{
-		Users: &userModel,
+		Users:   &userModel,
+		Rewards: &models.RewardScheduleModel{DB: db},
+		Events:  events,
+	}
+
+	seasonModel := models.SeasonModel{
+		DB: db,
 	}
 
 	betService := services.BetService{
 		Bets: &models.BetModel{
 			DB: db,
 		},
-		UserService:   &services.UserService{Users: &userModel},
+		UserService:   &userService,
 		MarketService: &marketService,
 		Outcome:       &outcomeModel,
+		Seasons:       &seasonModel,
+		Events:        events,
 	}
 
 	marketService.BetService = betService
 	marketService.UserService = userService
 
+	achievementService := services.AchievementService{
+		Achievements: &models.AchievementModel{DB: db},
+		Markets:      &marketModel,
+		UserService:  &userService,
+	}
+	events.Subscribe(achievementService.Handle)
+
+	notificationService := services.NotificationService{
+		Notifications: &models.NotificationModel{DB: db},
+		Users:         &userModel,
+	}
+	events.Subscribe(notificationService.Handle)
+
+	profileService := services.ProfileService{
+		Users:        &userModel,
+		Markets:      &marketModel,
+		Bets:         betService.Bets,
+		Seasons:      &seasonModel,
+		Achievements: &achievementService,
+	}
+
+	seasonService := services.SeasonService{
+		Seasons: &seasonModel,
+		Users:   &userModel,
+	}
+
+	leaderboardService := services.LeaderboardService{
+		Leaderboard: &models.LeaderboardModel{DB: db},
+		Seasons:     &seasonService,
+	}
+
+	groupService := services.GroupService{
+		Groups:        &groupModel,
+		MarketService: &marketService,
+	}
+
+	commentModel := models.CommentModel{
+		DB: db,
+	}
+
+	commentService := services.CommentService{
+		Comments:      &commentModel,
+		Users:         &userModel,
+		MarketService: &marketService,
+	}
+
+	moderationService := services.ModerationService{
+		Reports:       &models.ReportModel{DB: db},
+		Log:           &models.ModerationLogModel{DB: db},
+		Users:         &userModel,
+		Comments:      &commentModel,
+		MarketService: &marketService,
+	}
+
+	disputeService := services.DisputeService{
+		Disputes:      &disputeModel,
+		Bets:          betService.Bets,
+		MarketService: &marketService,
+	}
+
+	seriesService := services.SeriesService{
+		Series:        &models.SeriesModel{DB: db},
+		MarketService: &marketService,
+	}
+
+	eventService := services.EventService{
+		Events:        &eventModel,
+		MarketService: &marketService,
+	}
+
+	oracleService := services.OracleService{
+		Oracles:       &oracleModel,
+		MarketService: &marketService,
+	}
+
 	app := application{
 		infoLog:        infoLog,
 		errorLog:       errorLog,
 		templateCache:  tc,
 		formDecoder:    form.NewDecoder(),
 		users:          &userModel,
+		userService:    &userService,
 		betService:     &betService,
+		profileService: &profileService,
+		leaderboard:    &leaderboardService,
+		seasonService:  &seasonService,
+		achievements:   &achievementService,
 		marketService:  &marketService,
+		groupService:   &groupService,
+		comments:       &commentService,
+		moderation:     &moderationService,
+		disputes:       &disputeService,
+		series:         &seriesService,
+		eventPages:     &eventService,
+		oracles:        &oracleService,
+		notifications:  &notificationService,
+		fees:           &services.FeeService{Fees: &feeModel},
+		integrity:      &services.IntegrityService{Integrity: &integrityModel},
 		sessionManager: sesssionManager,
 		location:       location,
 	}
 
+	issuer := os.Getenv("OIDC_ISSUER")
+	if issuer != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		provider, err := oidc.NewProvider(ctx, oidc.Config{
+			Issuer:       issuer,
+			ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+			ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+			RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		}, nil)
+		cancel()
+
+		if err != nil {
+			errorLog.Printf("single sign-on disabled: %v", err)
+		} else {
+			app.oidc = provider
+		}
+	}
+
+	app.runEvery("leaderboard", 5*time.Minute, app.leaderboard.Refresh)
+	app.runEvery("seasons", time.Minute, app.seasonService.Advance)
+	app.runEvery("disputes", time.Minute, app.disputes.FinalizeDue)
+	app.runEvery("series", time.Minute, app.series.CreateDue)
+	app.runEvery("oracles", time.Minute, app.oracles.ResolveDue)
+
 	log.Printf("Starting server on %s", addr)
 	err = http.ListenAndServe(addr, app.routes())
 	log.Fatal(err)
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+var (
+	mdCodeRX   = regexp.MustCompile("`([^`\n]+)`")
+	mdBoldRX   = regexp.MustCompile(`\*\*([^*\n]+)\*\*`)
+	mdItalicRX = regexp.MustCompile(`(^|[^*\w])[*_]([^*_\n]+)[*_]`)
+	mdLinkRX   = regexp.MustCompile(`\[([^\]\n]+)\]\((https?://[^\s)]+)\)`)
+)
+
+// markdown renders the small subset of markdown allowed in comments:
+// paragraphs, line breaks, > quotes, **bold**, *italic*, `code` and
+// [links](https://...). The input is HTML-escaped before any markup is
+// added, so the only tags in the output are the ones produced here.
+func markdown(s string) template.HTML {
+	var b strings.Builder
+
+	for _, block := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n\n") {
+		block = strings.TrimSpace(block)
+		if block == "" {
+			continue
+		}
+
+		lines := strings.Split(block, "\n")
+		quote := true
+		for _, l := range lines {
+			if !strings.HasPrefix(l, ">") {
+				quote = false
+				break
+			}
+		}
+
+		for i, l := range lines {
+			if quote {
+				l = strings.TrimSpace(strings.TrimPrefix(l, ">"))
+			}
+			lines[i] = mdInline(template.HTMLEscapeString(l))
+		}
+
+		if quote {
+			b.WriteString(`<blockquote class="border-l-2 border-border-subtle pl-3 text-text-muted">`)
+			b.WriteString(strings.Join(lines, "<br>"))
+			b.WriteString("</blockquote>")
+			continue
+		}
+
+		b.WriteString("<p>")
+		b.WriteString(strings.Join(lines, "<br>"))
+		b.WriteString("</p>")
+	}
+
+	return template.HTML(b.String())
+}
+
+// mdInline formats an already escaped line. Code spans are split out first
+// so their contents are never formatted.
+func mdInline(line string) string {
+	var b strings.Builder
+	last := 0
+
+	for _, m := range mdCodeRX.FindAllStringSubmatchIndex(line, -1) {
+		b.WriteString(mdEmphasis(line[last:m[0]]))
+		b.WriteString(`<code class="px-1 rounded bg-border-subtle">`)
+		b.WriteString(line[m[2]:m[3]])
+		b.WriteString("</code>")
+		last = m[1]
+	}
+	b.WriteString(mdEmphasis(line[last:]))
+
+	return b.String()
+}
+
+// mdEmphasis formats links, bold and italics. URLs are left untouched so
+// underscores in them are not read as emphasis.
+func mdEmphasis(s string) string {
+	var b strings.Builder
+	last := 0
+
+	for _, m := range mdLinkRX.FindAllStringSubmatchIndex(s, -1) {
+		b.WriteString(mdStyle(s[last:m[0]]))
+		b.WriteString(`<a href="`)
+		b.WriteString(s[m[4]:m[5]])
+		b.WriteString(`" rel="nofollow noopener noreferrer" target="_blank" class="text-accent hover:underline">`)
+		b.WriteString(mdStyle(s[m[2]:m[3]]))
+		b.WriteString("</a>")
+		last = m[1]
+	}
+	b.WriteString(mdStyle(s[last:]))
+
+	return b.String()
+}
+
+func mdStyle(s string) string {
+	s = mdBoldRX.ReplaceAllString(s, "<strong>$1</strong>")
+	return mdItalicRX.ReplaceAllString(s, "$1<em>$2</em>")
+}
@@ -63,3 +63,49 @@ func (app *application) noSurf(next http.Handler) http.Handler {
 
 	return csrfHandler
 }
+
+func (app *application) requiresAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := app.getUserId(r)
+		if err != nil {
+			app.clientError(w, http.StatusForbidden)
+			return
+		}
+
+		isAdmin, err := app.users.IsAdmin(id)
+		if err != nil {
+			app.serverError(w, err)
+			return
+		}
+
+		if !isAdmin {
+			app.clientError(w, http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (app *application) requiresModerator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, err := app.getUserId(r)
+		if err != nil {
+			app.clientError(w, http.StatusForbidden)
+			return
+		}
+
+		role, err := app.users.Role(id)
+		if err != nil {
+			app.serverError(w, err)
+			return
+		}
+
+		if !role.CanModerate() {
+			app.clientError(w, http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
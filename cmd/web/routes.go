@@ -9,6 +9,8 @@ func (app *application) routes() http.Handler {
 	router := http.NewServeMux()
 	baseChain := web.Chain{app.sessionManager.LoadAndSave, app.logRequest, app.authenticate, app.noSurf}
 	authChain := append(baseChain, app.requiresAuthentication)
+	adminChain := append(authChain[:len(authChain):len(authChain)], app.requiresAdmin)
+	moderatorChain := append(authChain[:len(authChain):len(authChain)], app.requiresModerator)
 
 	fileServer := http.FileServer(
 		web.NeuteredFileSystem(http.Dir("./ui/static")),
@@ -26,16 +28,74 @@ func (app *application) routes() http.Handler {
 	router.Handle("GET /login", http.HandlerFunc(app.login))
 	router.Handle("POST /login", http.HandlerFunc(app.loginPost))
 
+	router.Handle("GET /auth/oidc/login", http.HandlerFunc(app.oidcLogin))
+	router.Handle("GET /auth/oidc/callback", http.HandlerFunc(app.oidcCallback))
+
+	router.Handle("GET /leaderboard", http.HandlerFunc(app.viewLeaderboard))
+
 	router.Handle("GET /account", http.HandlerFunc(app.account))
 
 	router.Handle("GET /markets/create", authChain.ThenFunc(app.createMarket))
 	router.Handle("POST /markets", authChain.ThenFunc(app.createMarketPost))
 	router.Handle("GET /markets/{id}", http.HandlerFunc(app.viewMarket))
+	router.Handle("GET /markets/{id}/quote", http.HandlerFunc(app.marketQuote))
 	router.Handle("POST /markets/{id}/bets", authChain.ThenFunc(app.createBetPost))
 	router.Handle("GET /markets/{id}/resolve", authChain.ThenFunc(app.resolveMarket))
 	router.Handle("POST /markets/{id}/resolve", authChain.ThenFunc(app.resolveMarketPost))
+	router.Handle("POST /markets/{id}/resolve/preview", authChain.ThenFunc(app.resolveMarketPreviewPost))
+	router.Handle("GET /markets/{id}/edit", authChain.ThenFunc(app.editMarket))
+	router.Handle("POST /markets/{id}/edit", authChain.ThenFunc(app.editMarketPost))
+	router.Handle("POST /markets/{id}/clarifications", authChain.ThenFunc(app.clarifyMarketPost))
+	router.Handle("POST /markets/{id}/disputes", authChain.ThenFunc(app.disputeMarketPost))
+	router.Handle("POST /markets/{id}/comments", authChain.ThenFunc(app.createCommentPost))
+	router.Handle("POST /markets/{id}/reports", authChain.ThenFunc(app.reportMarketPost))
+	router.Handle("GET /markets/{id}/resolver", authChain.ThenFunc(app.resolverRequest))
+	router.Handle("POST /markets/{id}/resolver", authChain.ThenFunc(app.resolverRequestPost))
+
+	router.Handle("GET /notifications", authChain.ThenFunc(app.notificationsPage))
+
+	router.Handle("GET /events", http.HandlerFunc(app.events))
+	router.Handle("POST /events", authChain.ThenFunc(app.eventsPost))
+	router.Handle("GET /events/{id}", http.HandlerFunc(app.viewEvent))
+
+	router.Handle("GET /series/{id}", http.HandlerFunc(app.viewSeries))
+	router.Handle("POST /series/{id}/stop", authChain.ThenFunc(app.stopSeriesPost))
+
+	router.Handle("POST /comments/{id}/edit", authChain.ThenFunc(app.editCommentPost))
+	router.Handle("POST /comments/{id}/delete", authChain.ThenFunc(app.deleteCommentPost))
+	router.Handle("POST /comments/{id}/remove", moderatorChain.ThenFunc(app.removeCommentPost))
+	router.Handle("POST /comments/{id}/reports", authChain.ThenFunc(app.reportCommentPost))
+
+	router.Handle("GET /moderation", moderatorChain.ThenFunc(app.moderationQueue))
+	router.Handle("POST /moderation/actions", moderatorChain.ThenFunc(app.moderationActionPost))
 
 	router.Handle("POST /users/me/daily-claim", authChain.ThenFunc(app.dailyClaimPost))
+	router.Handle("POST /users/me/profile", authChain.ThenFunc(app.profileSettingsPost))
+	router.Handle("GET /users/{username}", http.HandlerFunc(app.viewProfile))
+
+	router.Handle("GET /groups", authChain.ThenFunc(app.groups))
+	router.Handle("POST /groups", authChain.ThenFunc(app.groupsPost))
+	router.Handle("GET /groups/{id}", authChain.ThenFunc(app.viewGroup))
+	router.Handle("POST /groups/{id}/invites", authChain.ThenFunc(app.groupInvitesPost))
+	router.Handle("POST /groups/{id}/invites/{inviteID}/revoke", authChain.ThenFunc(app.groupInviteRevokePost))
+	router.Handle("POST /groups/{id}/members/{userID}/role", authChain.ThenFunc(app.groupMemberRolePost))
+	router.Handle("GET /invites/{token}", authChain.ThenFunc(app.viewInvite))
+	router.Handle("POST /invites/{token}", authChain.ThenFunc(app.joinGroupPost))
+
+	router.Handle("GET /admin", adminChain.ThenFunc(app.adminDashboard))
+	router.Handle("GET /admin/seasons", adminChain.ThenFunc(app.adminSeasons))
+	router.Handle("POST /admin/seasons", adminChain.ThenFunc(app.adminSeasonsPost))
+	router.Handle("GET /admin/rewards", adminChain.ThenFunc(app.adminRewards))
+	router.Handle("POST /admin/rewards", adminChain.ThenFunc(app.adminRewardsPost))
+	router.Handle("GET /admin/fees", adminChain.ThenFunc(app.adminFees))
+	router.Handle("POST /admin/fees", adminChain.ThenFunc(app.adminFeesPost))
+	router.Handle("GET /admin/integrity", adminChain.ThenFunc(app.adminIntegrity))
+	router.Handle("POST /admin/integrity", adminChain.ThenFunc(app.adminIntegrityPost))
+	router.Handle("GET /admin/disputes", adminChain.ThenFunc(app.adminDisputes))
+	router.Handle("POST /admin/disputes/{id}", adminChain.ThenFunc(app.adminDisputePost))
+	router.Handle("GET /admin/oracles", adminChain.ThenFunc(app.adminOracles))
+	router.Handle("POST /admin/oracles/{id}/retry", adminChain.ThenFunc(app.adminOracleRetryPost))
+	router.Handle("POST /admin/markets/{id}/corrections", adminChain.ThenFunc(app.adminCorrectionPost))
 
 	return baseChain.Then(router)
 }
@@ -1,31 +1,85 @@
 package main
 
 import (
+	"fmt"
 	"foresee/cmd/web/viewmodels"
 	"foresee/internal/models"
+	"foresee/internal/oracle"
 	"foresee/internal/services"
 	"html/template"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/justinas/nosurf"
 )
 
 type templateData struct {
 	IsAuthenticated     bool
+	IsAdmin             bool
+	IsModerator         bool
+	UserID              uuid.UUID
+	OIDCEnabled         bool
 	Balance             int
 	CanClaimDailyReward bool
+	DailyRewardAmount   int
+	RewardStreak        int
+	UnreadNotifications int
 	Flash               string
 	FlashError          string
 	Form                any
 	CSRFToken           string
 	MarketCategories    []models.Category
 	ResolverTypes       []models.ResolverType
+	MarketKinds         []models.MarketKind
+	OracleKinds         []oracle.Kind
+	OracleOperators     []oracle.Operator
 	BetHistory          []models.BetHistoryRow
+	User                models.User
+	Profile             services.Profile
+	Leaderboard         services.Leaderboard
+	LeaderboardSorts    []models.LeaderboardSort
+	Seasons             []models.Season
+	Achievements        []services.Achievement
+	Groups              []models.Group
+	Group               services.GroupPage
+	GroupRoles          []models.GroupRole
+	Invite              models.GroupInvite
+	Comments            services.CommentPage
+	ReportReasons       []models.ReportReason
+	DisputeWindows      []int
+	ReportedItems       []models.ReportedItem
+	ModerationLog       []models.ModerationLogEntry
+	Disputes            []models.Dispute
+	DisputeCases        []services.DisputeCase
+	CanDispute          bool
+	Corrections         []models.ResolutionCorrection
+	Revisions           []services.RevisionGroup
+	FeeSchedule         models.FeeSchedule
+	FeeIncome           []models.FeeEntry
+	FeeEntries          []models.FeeEntry
+	Treasury            int
+	IntegrityRules      []models.IntegrityRule
+	FlaggedBets         []models.Bet
+	Liquidity           []models.LiquidityPosition
+	Series              services.SeriesPage
+	Events              []models.Event
+	PastEvents          []models.Event
+	Event               services.EventPage
+	EventFilter         *models.Event
+	Oracle              *models.Oracle
+	FailedOracles       []models.Oracle
+	Notifications       []models.Notification
 
 	Markets            []viewmodels.MarketView
 	Market             viewmodels.MarketView
+	Condition          *viewmodels.ConditionView
+	Conditionals       []viewmodels.ConditionView
+	EventMarkets       viewmodels.EventView
+	PayoutPreview      *viewmodels.PayoutPreviewView
+	OutcomePreviews    []viewmodels.PayoutPreviewView
 	PendingResolutions []models.Market
 }
 
@@ -34,8 +88,14 @@ func (app *application) newTemplateData(r *http.Request) *templateData {
 		Flash:            app.sessionManager.PopString(r.Context(), "flash"),
 		FlashError:       app.sessionManager.PopString(r.Context(), "flash_error"),
 		IsAuthenticated:  isAuthenticated(r),
+		OIDCEnabled:      app.oidc != nil,
 		MarketCategories: models.AllCategories(),
 		ResolverTypes:    models.AllResolverTypes(),
+		MarketKinds:      models.AllMarketKinds(),
+		OracleKinds:      oracle.AllKinds(),
+		OracleOperators:  oracle.AllOperators(),
+		ReportReasons:    models.AllReportReasons(),
+		DisputeWindows:   models.AllDisputeWindows(),
 		Balance:          0,
 		CSRFToken:        nosurf.Token(r),
 	}
@@ -49,17 +109,62 @@ func (app *application) newTemplateData(r *http.Request) *templateData {
 		return data
 	}
 
-	balance, lastClaimedAt, err := app.users.GetTemplateInfo(id)
+	user, err := app.users.GetTemplateInfo(id)
 	if err != nil {
 		return data
 	}
 
-	data.Balance = balance
-	data.CanClaimDailyReward = services.CanClaimReward(time.Now(), lastClaimedAt.Time)
+	data.Balance = user.Balance
+	data.UserID = user.ID
+	data.IsAdmin = user.Role == models.RoleAdmin
+	data.IsModerator = user.Role.CanModerate()
+
+	unread, err := app.notifications.Unread(user.ID)
+	if err != nil {
+		return data
+	}
+	data.UnreadNotifications = unread
+
+	schedule, err := app.userService.RewardSchedule()
+	if err != nil {
+		return data
+	}
+
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		loc = app.location
+	}
+
+	today := services.ClaimDay(time.Now(), loc)
+	streak := services.CurrentStreak(schedule, today, user.LastClaimedAt, user.RewardStreak)
+
+	data.RewardStreak = streak
+	data.CanClaimDailyReward = services.CanClaimReward(today, user.LastClaimedAt)
+	data.DailyRewardAmount = services.RewardAmount(schedule, services.NextStreak(schedule, today, user.LastClaimedAt, user.RewardStreak))
 
 	return data
 }
 
+func percent(f float64) string {
+	return fmt.Sprintf("%.0f%%", f*100)
+}
+
+// bps formats basis points as a percentage, e.g. 250 as "2.5%".
+func bps(n int) string {
+	return strconv.FormatFloat(float64(n)/100, 'f', -1, 64) + "%"
+}
+
+func decimal(f float64) string {
+	return fmt.Sprintf("%.3f", f)
+}
+
+var functions = template.FuncMap{
+	"percent":  percent,
+	"bps":      bps,
+	"decimal":  decimal,
+	"markdown": markdown,
+}
+
 func newTemplateCache() (map[string]*template.Template, error) {
 	cache := map[string]*template.Template{}
 
@@ -71,7 +176,7 @@ func newTemplateCache() (map[string]*template.Template, error) {
 	for _, page := range pages {
 		name := filepath.Base(page)
 
-		ts, err := template.New(name).ParseFiles("./ui/html/base.html")
+		ts, err := template.New(name).Funcs(functions).ParseFiles("./ui/html/base.html")
 		if err != nil {
 			return nil, err
 		}
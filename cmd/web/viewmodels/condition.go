@@ -0,0 +1,60 @@
+package viewmodels
+
+import "foresee/internal/models"
+
+// ConditionView links a conditional market to the market it depends on.
+// MarketID, MarketTitle and Status describe the other side of the link:
+// the parent on a conditional market's page and the child on its parent's.
+type ConditionView struct {
+	MarketID     string
+	MarketTitle  string
+	Status       string
+	GroupID      string
+	OutcomeID    string
+	OutcomeLabel string
+}
+
+// NewConditionView describes the market m depends on, or returns nil when m
+// is not conditional.
+func NewConditionView(m models.Market) *ConditionView {
+	if m.ConditionMarketID == nil || m.ConditionOutcomeID == nil {
+		return nil
+	}
+
+	view := &ConditionView{
+		MarketID:  m.ConditionMarketID.String(),
+		OutcomeID: m.ConditionOutcomeID.String(),
+	}
+	if m.ConditionMarketTitle != nil {
+		view.MarketTitle = *m.ConditionMarketTitle
+	}
+	if m.ConditionMarketStatus != nil {
+		view.Status = *m.ConditionMarketStatus
+	}
+	if m.ConditionOutcomeLabel != nil {
+		view.OutcomeLabel = *m.ConditionOutcomeLabel
+	}
+
+	return view
+}
+
+// NewConditionalViews describes the markets depending on a market, as
+// returned by MarketService.ConditionalOn.
+func NewConditionalViews(markets []models.Market) []ConditionView {
+	views := make([]ConditionView, len(markets))
+	for i, m := range markets {
+		views[i] = ConditionView{
+			MarketID:    m.ID.String(),
+			MarketTitle: m.Title,
+			Status:      m.Status,
+		}
+		if m.ConditionOutcomeID != nil {
+			views[i].OutcomeID = m.ConditionOutcomeID.String()
+		}
+		if m.ConditionOutcomeLabel != nil {
+			views[i].OutcomeLabel = *m.ConditionOutcomeLabel
+		}
+	}
+
+	return views
+}
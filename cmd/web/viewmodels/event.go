@@ -0,0 +1,69 @@
+package viewmodels
+
+import (
+	"foresee/internal/models"
+	"time"
+)
+
+// EventView lays out an event's markets: those in a mutually exclusive
+// group together, the rest on their own.
+type EventView struct {
+	Markets   []MarketView
+	Exclusive []ExclusiveGroupView
+}
+
+// ExclusiveGroupView shows the yes probabilities of mutually exclusive
+// markets scaled to add up to one, as at most one of them can happen. Total
+// is what they add up to unscaled, above one when the group is overpriced.
+type ExclusiveGroupView struct {
+	Name    string
+	Total   float64
+	Markets []ExclusiveMarketView
+}
+
+type ExclusiveMarketView struct {
+	MarketView
+	Yes        float64
+	Normalized float64
+}
+
+func NewEventView(markets []*models.Market, loc *time.Location) EventView {
+	var view EventView
+	groups := map[string]int{}
+
+	for _, m := range markets {
+		mv := NewMarketView(*m, loc)
+		if mv.EventGroup == "" {
+			view.Markets = append(view.Markets, mv)
+			continue
+		}
+
+		i, ok := groups[mv.EventGroup]
+		if !ok {
+			i = len(view.Exclusive)
+			groups[mv.EventGroup] = i
+			view.Exclusive = append(view.Exclusive, ExclusiveGroupView{Name: mv.EventGroup})
+		}
+
+		em := ExclusiveMarketView{MarketView: mv}
+		for _, o := range mv.Outcomes {
+			if o.Label == models.YesLabel {
+				em.Yes = o.Probability
+			}
+		}
+
+		view.Exclusive[i].Total += em.Yes
+		view.Exclusive[i].Markets = append(view.Exclusive[i].Markets, em)
+	}
+
+	for _, g := range view.Exclusive {
+		if g.Total == 0 {
+			continue
+		}
+		for j := range g.Markets {
+			g.Markets[j].Normalized = g.Markets[j].Yes / g.Total
+		}
+	}
+
+	return view
+}
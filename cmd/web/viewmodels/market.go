@@ -1,29 +1,97 @@
 package viewmodels
 
 import (
+	"fmt"
 	"foresee/internal/models"
+	"strconv"
 	"time"
 )
 
 type MarketView struct {
-	ID          string
-	Title       string
-	Description string
-	Category    string
-	Resolver    string
-	ExpiresAt   string
-	Status      string
-	CreatedBy   string
-	Outcomes    []OutcomeView
-	TotalPool   int
+	ID           string
+	Title        string
+	Description  string
+	Category     string
+	Resolver     string
+	ExpiresAt    string
+	Status       string
+	CreatedBy    string
+	CreatedByID  string
+	ResolverUser string
+	ResolverID   string
+	ResolvedBy   string
+	GroupID      string
+	GroupName    string
+	Hidden       bool
+	ProposedBy   string
+	FinalizesAt  string
+	Outcomes     []OutcomeView
+	TotalPool    int
+	Liquidity    int
+
+	// HasBets is set once anyone has bet, as opposed to the pools only
+	// holding seeded liquidity.
+	HasBets bool
+
+	// Split is set when the market was resolved to more than one outcome.
+	Split bool
+
+	// Fees taken from the pool at settlement, empty when there are none.
+	PlatformFee string
+	CreatorFee  string
+
+	// IntegrityRule is what happens when the resolver bets, see
+	// models.IntegrityRule.
+	IntegrityRule string
+
+	// Scalar markets show their range and, once answered, the value they
+	// were resolved to.
+	Scalar        bool
+	ScalarLower   string
+	ScalarUpper   string
+	ScalarLog     bool
+	ResolvedValue string
+
+	// Bucketed markets show their outcomes as a histogram of the pools.
+	// Dates is set for date markets, which are resolved with a date.
+	Bucketed    bool
+	Dates       bool
+	BucketRange string
+
+	// Condition is the market this one depends on, if any.
+	Condition *ConditionView
+
+	// SeriesID links to the series the market was created for, if any.
+	SeriesID string
+
+	// EventID links to the event the market is listed under, if any.
+	// EventGroup names the mutually exclusive markets of the event it
+	// belongs to.
+	EventID    string
+	EventTitle string
+	EventGroup string
+}
+
+// number formats a scalar bound or answer without trailing zeros.
+func number(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// date turns a date market's Unix timestamp back into a time.
+func date(unix float64, loc *time.Location) time.Time {
+	return time.Unix(int64(unix), 0).In(loc)
 }
 
 func NewMarketView(m models.Market, loc *time.Location) MarketView {
 	outcomes := make([]OutcomeView, len(m.Outcomes))
 	totalPool := 0
+	split := false
 	for i, o := range m.Outcomes {
 		outcomes[i] = NewOutcomeView(o)
+		outcomes[i].IsWinner = m.ResolvedOutcomeID != nil && *m.ResolvedOutcomeID == o.ID
+		outcomes[i].IsProposed = m.ProposedOutcomeID != nil && *m.ProposedOutcomeID == o.ID
 		totalPool += o.PoolAmount
+		split = split || (o.ResolvedShareBps != nil && *o.ResolvedShareBps > 0 && *o.ResolvedShareBps < 10000)
 	}
 
 	status := m.Status
@@ -31,15 +99,141 @@ func NewMarketView(m models.Market, loc *time.Location) MarketView {
 		status = "expired"
 	}
 
+	resolverUser := ""
+	if m.ResolverUsername != nil {
+		resolverUser = *m.ResolverUsername
+	}
+
+	resolverID := ""
+	if m.ResolverRef != nil {
+		resolverID = m.ResolverRef.String()
+	}
+
+	resolvedBy := ""
+	if m.ResolvedByUsername != nil {
+		resolvedBy = *m.ResolvedByUsername
+	}
+
+	proposedBy, finalizesAt := "", ""
+	if m.ProposedByUsername != nil {
+		proposedBy = *m.ProposedByUsername
+	}
+	if m.FinalizesAt != nil {
+		finalizesAt = m.FinalizesAt.In(loc).Format("2006-01-02 15:04")
+	}
+
+	seriesID := ""
+	if m.SeriesID != nil {
+		seriesID = m.SeriesID.String()
+	}
+
+	groupID, groupName := "", ""
+	if m.GroupID != nil {
+		groupID = m.GroupID.String()
+	}
+	if m.GroupName != nil {
+		groupName = *m.GroupName
+	}
+
+	platformFee, creatorFee := "", ""
+	if m.PlatformFeeBps > 0 {
+		platformFee = percent(m.PlatformFeeBps)
+	}
+	if m.CreatorFeeBps > 0 {
+		creatorFee = percent(m.CreatorFeeBps)
+	}
+
+	lower, upper, value := "", "", ""
+	if m.ScalarLower != nil {
+		lower = number(*m.ScalarLower)
+	}
+	if m.ScalarUpper != nil {
+		upper = number(*m.ScalarUpper)
+	}
+	if m.ResolvedValue != nil {
+		value = number(*m.ResolvedValue)
+		if m.Kind == models.KindDate {
+			value = date(*m.ResolvedValue, loc).Format("2006-01-02")
+		}
+	}
+
+	bucketRange := ""
+	if m.BucketLower != nil && m.BucketUpper != nil && m.BucketSize != nil {
+		switch m.Kind {
+		case models.KindBucket:
+			bucketRange = fmt.Sprintf("%s to %s in steps of %s", number(*m.BucketLower), number(*m.BucketUpper), number(*m.BucketSize))
+		case models.KindDate:
+			// The upper bound is the midnight after the last day.
+			through := date(*m.BucketUpper, loc).AddDate(0, 0, -1)
+			bucketRange = fmt.Sprintf("%s to %s in %s day buckets", date(*m.BucketLower, loc).Format("2006-01-02"), through.Format("2006-01-02"), number(*m.BucketSize/86400))
+		}
+	}
+
+	maxPool := 0
+	for _, o := range outcomes {
+		maxPool = max(maxPool, o.PoolAmount)
+	}
+	if maxPool > 0 {
+		for i := range outcomes {
+			outcomes[i].Bar = outcomes[i].PoolAmount * 100 / maxPool
+			outcomes[i].Probability = float64(outcomes[i].PoolAmount) / float64(totalPool)
+		}
+	}
+
+	eventID, eventTitle, eventGroup := "", "", ""
+	if m.EventID != nil {
+		eventID = m.EventID.String()
+	}
+	if m.EventTitle != nil {
+		eventTitle = *m.EventTitle
+	}
+	if m.EventGroup != nil {
+		eventGroup = *m.EventGroup
+	}
+
 	return MarketView{
-		ID:          m.ID.String(),
-		Title:       m.Title,
-		Description: m.Description,
-		Category:    string(m.Category),
-		Resolver:    string(m.ResolverType),
-		ExpiresAt:   m.ExpiresAt.In(loc).Format("2006-01-02 15:04"),
-		Status:      status,
-		Outcomes:    outcomes,
-		TotalPool:   totalPool,
+		ID:           m.ID.String(),
+		Title:        m.Title,
+		Description:  m.Description,
+		Category:     string(m.Category),
+		Resolver:     string(m.ResolverType),
+		ExpiresAt:    m.ExpiresAt.In(loc).Format("2006-01-02 15:04"),
+		Status:       status,
+		CreatedBy:    m.CreatorUsername,
+		CreatedByID:  m.CreatedBy.String(),
+		ResolverUser: resolverUser,
+		ResolverID:   resolverID,
+		ResolvedBy:   resolvedBy,
+		GroupID:      groupID,
+		GroupName:    groupName,
+		Hidden:       m.HiddenAt != nil,
+		ProposedBy:   proposedBy,
+		FinalizesAt:  finalizesAt,
+		Outcomes:     outcomes,
+		TotalPool:    totalPool,
+		Liquidity:    m.Liquidity,
+		HasBets:      totalPool > m.Liquidity,
+		Split:        split,
+		PlatformFee:  platformFee,
+		CreatorFee:   creatorFee,
+
+		IntegrityRule: string(m.IntegrityRule),
+
+		Scalar:        m.Kind == models.KindScalar,
+		ScalarLower:   lower,
+		ScalarUpper:   upper,
+		ScalarLog:     m.ScalarLog,
+		ResolvedValue: value,
+
+		Bucketed:    m.Kind.Bucketed(),
+		Dates:       m.Kind == models.KindDate,
+		BucketRange: bucketRange,
+
+		Condition: NewConditionView(m),
+		SeriesID:  seriesID,
+
+		EventID:    eventID,
+		EventTitle: eventTitle,
+		EventGroup: eventGroup,
 	}
 }
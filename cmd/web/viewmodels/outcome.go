@@ -1,17 +1,41 @@
 package viewmodels
 
-import "foresee/internal/models"
+import (
+	"foresee/internal/models"
+	"strconv"
+)
 
 type OutcomeView struct {
 	ID         string
 	Label      string
 	PoolAmount int
+	IsWinner   bool
+	IsProposed bool
+	Share      string
+
+	// Bar is the outcome's pool as a percentage of the largest one, for
+	// drawing bucket markets as a histogram.
+	Bar int
+
+	// Probability is the outcome's share of the market's pool.
+	Probability float64
+}
+
+// percent formats basis points as a percentage, e.g. 250 as "2.5%".
+func percent(bps int) string {
+	return strconv.FormatFloat(float64(bps)/100, 'f', -1, 64) + "%"
 }
 
 func NewOutcomeView(outcome models.Outcome) OutcomeView {
+	share := ""
+	if outcome.ResolvedShareBps != nil {
+		share = percent(*outcome.ResolvedShareBps)
+	}
+
 	return OutcomeView{
 		ID:         outcome.ID.String(),
 		Label:      outcome.Label,
 		PoolAmount: outcome.PoolAmount,
+		Share:      share,
 	}
 }
@@ -0,0 +1,113 @@
+package viewmodels
+
+import (
+	"foresee/internal/services"
+
+	"github.com/google/uuid"
+)
+
+// PayoutLineView is a bet or, when Liquidity is set, a seeded position.
+// Treasury liquidity has no Username.
+type PayoutLineView struct {
+	Username  string
+	Liquidity bool
+	Outcome   string
+	Amount    int
+	Payout    int
+	Remainder int
+}
+
+type OutcomePayoutView struct {
+	Label     string
+	Share     string
+	Pool      int
+	Remainder int
+	Bettors   int
+}
+
+// PayoutPreviewView shows a settlement before it is made, including its
+// fees and the coins handed out to settle rounding.
+type PayoutPreviewView struct {
+	Label       string
+	Pool        int
+	Paid        int
+	PlatformFee int
+	CreatorFee  int
+	Outcomes    []OutcomePayoutView
+	Lines       []PayoutLineView
+}
+
+func NewPayoutPreviewView(p services.PayoutPreview, outcomes []OutcomeView) PayoutPreviewView {
+	labels := make(map[string]string, len(outcomes))
+	for _, o := range outcomes {
+		labels[o.ID] = o.Label
+	}
+
+	view := PayoutPreviewView{
+		Pool:        p.Plan.Pool,
+		Paid:        p.Plan.Paid(),
+		PlatformFee: p.Plan.PlatformFee,
+		CreatorFee:  p.Plan.CreatorFee,
+	}
+
+	bettors := make(map[string]int)
+	for i, l := range p.Plan.Lines {
+		outcomeID := l.OutcomeID.String()
+
+		line := PayoutLineView{
+			Outcome:   labels[outcomeID],
+			Amount:    l.Amount,
+			Payout:    l.Payout(),
+			Remainder: l.Remainder,
+		}
+
+		if i < len(p.Liquidity) {
+			line.Liquidity = true
+			if u := p.Liquidity[i].Username; u != nil {
+				line.Username = *u
+			}
+		} else {
+			line.Username = p.Bets[i-len(p.Liquidity)].Username
+			bettors[outcomeID]++
+		}
+
+		view.Lines = append(view.Lines, line)
+	}
+
+	for _, o := range p.Plan.Outcomes {
+		outcomeID := o.OutcomeID.String()
+
+		view.Outcomes = append(view.Outcomes, OutcomePayoutView{
+			Label:     labels[outcomeID],
+			Share:     percent(o.ShareBps),
+			Pool:      o.Total(),
+			Remainder: o.Remainder,
+			Bettors:   bettors[outcomeID],
+		})
+	}
+
+	return view
+}
+
+// NewOutcomePreviewViews lists the payouts of each outcome winning outright,
+// in the market's outcome order.
+func NewOutcomePreviewViews(previews map[uuid.UUID]services.PayoutPreview, outcomes []OutcomeView) []PayoutPreviewView {
+	byID := make(map[string]services.PayoutPreview, len(previews))
+	for id, p := range previews {
+		byID[id.String()] = p
+	}
+
+	views := make([]PayoutPreviewView, 0, len(outcomes))
+	for _, o := range outcomes {
+		p, ok := byID[o.ID]
+		if !ok {
+			continue
+		}
+
+		view := NewPayoutPreviewView(p, outcomes)
+		view.Label = o.Label
+		views = append(views, view)
+	}
+
+	return views
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type UserAchievement struct {
+	UserID    uuid.UUID
+	Code      string
+	Bonus     int
+	AwardedAt time.Time
+}
+
+type AchievementModel struct {
+	DB *sql.DB
+}
+
+// Award records the achievement and reports whether it is new, so awarding
+// the same achievement twice is a no-op.
+func (m *AchievementModel) Award(tx *sql.Tx, userID uuid.UUID, code string, bonus int) (bool, error) {
+	stmt := `INSERT INTO user_achievements (user_id, code, bonus)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, code) DO NOTHING`
+
+	res, err := tx.Exec(stmt, userID, code, bonus)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// Revoke removes the achievement and returns the bonus paid for it. It
+// reports false when the user did not have it.
+func (m *AchievementModel) Revoke(tx *sql.Tx, userID uuid.UUID, code string) (int, bool, error) {
+	stmt := `DELETE FROM user_achievements
+		WHERE user_id = $1
+		  AND code = $2
+		RETURNING bonus`
+
+	var bonus int
+	err := tx.QueryRow(stmt, userID, code).Scan(&bonus)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	return bonus, true, nil
+}
+
+// HasWin reports whether userID bet on the winning outcome of a resolved
+// market, only counting bets placed at an implied probability under
+// maxProbability unless it is nil.
+func (m *AchievementModel) HasWin(userID uuid.UUID, maxProbability *float64) (bool, error) {
+	var won bool
+	stmt := `SELECT EXISTS(
+		SELECT true
+		FROM bets b
+		JOIN markets m ON m.id = b.market_id
+		WHERE b.user_id = $1
+		  AND m.status = 'resolved'
+		  AND b.outcome_id = m.resolved_outcome_id
+		  AND ($2::float8 IS NULL OR b.implied_probability < $2)
+	)`
+
+	err := m.DB.QueryRow(stmt, userID, maxProbability).Scan(&won)
+
+	return won, err
+}
+
+func (m *AchievementModel) ForUser(userID uuid.UUID) ([]UserAchievement, error) {
+	stmt := `SELECT user_id, code, bonus, awarded_at
+		FROM user_achievements
+		WHERE user_id = $1
+		ORDER BY awarded_at`
+
+	rows, err := m.DB.Query(stmt, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var achievements []UserAchievement
+
+	for rows.Next() {
+		var a UserAchievement
+		err = rows.Scan(&a.UserID, &a.Code, &a.Bonus, &a.AwardedAt)
+		if err != nil {
+			return nil, err
+		}
+		achievements = append(achievements, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return achievements, nil
+}
@@ -7,17 +7,26 @@ import (
 )
 
 type BetHistoryRow struct {
-	BetID           uuid.UUID
-	MarketID        uuid.UUID
-	MarketTitle     string
-	MarketStatus    string
-	OutcomeID       uuid.UUID
-	OutcomeLabel    string
-	Amount          int
-	Payout          *int
-	Result          string
-	BetCreatedAt    time.Time
-	MarketExpiresAt time.Time
+	BetID              uuid.UUID
+	MarketID           uuid.UUID
+	MarketTitle        string
+	MarketStatus       string
+	MarketGroupID      *uuid.UUID
+	OutcomeID          uuid.UUID
+	OutcomeLabel       string
+	Amount             int
+	Payout             *int
+	ImpliedProbability *float64
+	Result             string
+	BetCreatedAt       time.Time
+	MarketExpiresAt    time.Time
+	RefundedAt         *time.Time
+
+	// Set when an admin corrected the market's resolution after this bet
+	// was first settled.
+	CorrectedAt      *time.Time
+	PreviousPayout   int
+	CorrectionReason string
 }
 
 func (m *BetModel) GetUserBetHistory(userID uuid.UUID) ([]BetHistoryRow, error) {
@@ -27,15 +36,29 @@ func (m *BetModel) GetUserBetHistory(userID uuid.UUID) ([]BetHistoryRow, error)
 		m.id,
 		m.title,
 		m.status,
+		m.group_id,
 		o.id,
 		o.label,
 		b.amount,
 		b.payout_amount,
+		b.implied_probability,
 		b.created_at,
-		m.expires_at
+		m.expires_at,
+		b.refunded_at,
+		pc.created_at,
+		COALESCE(pc.previous_payout, 0),
+		COALESCE(pc.reason, '')
 	FROM bets b
 	JOIN markets m ON m.id = b.market_id
 	JOIN outcomes o ON o.id = b.outcome_id
+	LEFT JOIN LATERAL (
+		SELECT p.created_at, p.previous_payout, c.reason
+		FROM payout_corrections p
+		JOIN resolution_corrections c ON c.id = p.correction_id
+		WHERE p.bet_id = b.id
+		ORDER BY p.created_at DESC
+		LIMIT 1
+	) pc ON true
 	WHERE b.user_id = $1
 	ORDER BY b.created_at DESC`
 
@@ -55,18 +78,26 @@ func (m *BetModel) GetUserBetHistory(userID uuid.UUID) ([]BetHistoryRow, error)
 			&row.MarketID,
 			&row.MarketTitle,
 			&row.MarketStatus,
+			&row.MarketGroupID,
 			&row.OutcomeID,
 			&row.OutcomeLabel,
 			&row.Amount,
 			&row.Payout,
+			&row.ImpliedProbability,
 			&row.BetCreatedAt,
 			&row.MarketExpiresAt,
+			&row.RefundedAt,
+			&row.CorrectedAt,
+			&row.PreviousPayout,
+			&row.CorrectionReason,
 		)
 		if err != nil {
 			return nil, err
 		}
 
-		if row.MarketStatus != "resolved" {
+		if row.RefundedAt != nil {
+			row.Result = "refunded"
+		} else if row.MarketStatus != "resolved" {
 			row.Result = "pending"
 		} else if row.Payout != nil && *row.Payout > 0 {
 			row.Result = "win"
@@ -3,6 +3,7 @@ package models
 import (
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -15,6 +16,20 @@ type Bet struct {
 	OutcomeID uuid.UUID
 	Amount    int
 	CreatedAt sql.NullTime
+
+	ImpliedProbability *float64
+	Payout             *int
+
+	// SeasonClosed is set when the bet was placed in a season that has
+	// already been archived, see SeasonModel.ArchiveStandings.
+	SeasonClosed bool
+
+	// FlaggedAt is set on bets the market's resolver placed under
+	// IntegrityFlag.
+	FlaggedAt *time.Time
+
+	Username     string
+	OutcomeLabel string
 }
 
 type BetModel struct {
@@ -23,10 +38,11 @@ type BetModel struct {
 
 const MinimumBetAmount int = 100
 
-func (m *BetModel) Place(tx *sql.Tx, userID uuid.UUID, marketID uuid.UUID, outcomeID uuid.UUID, amount int) error {
-	stmt := `INSERT INTO bets (user_id, market_id, outcome_id, amount) VALUES ($1, $2, $3, $4)`
+func (m *BetModel) Place(tx *sql.Tx, userID uuid.UUID, marketID uuid.UUID, outcomeID uuid.UUID, amount int, impliedProbability float64, seasonID *uuid.UUID, flagged bool) error {
+	stmt := `INSERT INTO bets (user_id, market_id, outcome_id, amount, implied_probability, season_id, flagged_at)
+		VALUES ($1, $2, $3, $4, $5, $6, CASE WHEN $7::boolean THEN NOW() END)`
 
-	_, err := tx.Exec(stmt, userID, marketID, outcomeID, amount)
+	_, err := tx.Exec(stmt, userID, marketID, outcomeID, amount, impliedProbability, seasonID, flagged)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		ok := errors.As(err, &pgErr)
@@ -39,7 +55,12 @@ func (m *BetModel) Place(tx *sql.Tx, userID uuid.UUID, marketID uuid.UUID, outco
 }
 
 func (m *BetModel) ForMarketForUpdate(tx *sql.Tx, marketID uuid.UUID) ([]Bet, error) {
-	stmt := `SELECT id, user_id, amount, outcome_id FROM bets WHERE market_id = $1 FOR UPDATE`
+	stmt := `SELECT b.id, b.user_id, b.amount, b.outcome_id, b.implied_probability, b.payout_amount, COALESCE(s.status = 'archived', false)
+		FROM bets b
+		LEFT JOIN seasons s ON s.id = b.season_id
+		WHERE b.market_id = $1
+		ORDER BY b.created_at, b.id
+		FOR UPDATE OF b`
 
 	rows, err := tx.Query(stmt, marketID)
 	if err != nil {
@@ -51,7 +72,40 @@ func (m *BetModel) ForMarketForUpdate(tx *sql.Tx, marketID uuid.UUID) ([]Bet, er
 
 	for rows.Next() {
 		var b Bet
-		err = rows.Scan(&b.ID, &b.UserID, &b.Amount, &b.OutcomeID)
+		err = rows.Scan(&b.ID, &b.UserID, &b.Amount, &b.OutcomeID, &b.ImpliedProbability, &b.Payout, &b.SeasonClosed)
+		if err != nil {
+			return nil, err
+		}
+		bets = append(bets, b)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return bets, nil
+}
+
+// ForMarket lists the market's bets with their bettors in the order they were
+// placed, matching ForMarketForUpdate.
+func (m *BetModel) ForMarket(marketID uuid.UUID) ([]Bet, error) {
+	stmt := `SELECT b.id, b.user_id, u.username, b.amount, b.outcome_id
+		FROM bets b
+		JOIN users u ON u.id = b.user_id
+		WHERE b.market_id = $1
+		ORDER BY b.created_at, b.id`
+
+	rows, err := m.DB.Query(stmt, marketID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bets []Bet
+
+	for rows.Next() {
+		var b Bet
+		err = rows.Scan(&b.ID, &b.UserID, &b.Username, &b.Amount, &b.OutcomeID)
 		if err != nil {
 			return nil, err
 		}
@@ -70,3 +124,61 @@ func (m *BetModel) SetPayout(tx *sql.Tx, betID uuid.UUID, payout int) error {
 	_, err := tx.Exec(stmt, payout, betID)
 	return err
 }
+
+func (m *BetModel) Refund(tx *sql.Tx, betID uuid.UUID) error {
+	stmt := `UPDATE bets SET refunded_at = NOW() WHERE id = $1`
+	_, err := tx.Exec(stmt, betID)
+	return err
+}
+
+func (m *BetModel) CountForMarket(tx *sql.Tx, marketID uuid.UUID) (int, error) {
+	var count int
+	stmt := `SELECT COUNT(*) FROM bets WHERE market_id = $1`
+
+	err := tx.QueryRow(stmt, marketID).Scan(&count)
+
+	return count, err
+}
+
+func (m *BetModel) Exists(marketID, userID uuid.UUID) (bool, error) {
+	var exists bool
+	stmt := `SELECT EXISTS(SELECT true FROM bets WHERE market_id = $1 AND user_id = $2)`
+
+	err := m.DB.QueryRow(stmt, marketID, userID).Scan(&exists)
+
+	return exists, err
+}
+
+// Flagged lists the market's flagged bets with their bettors and outcomes.
+func (m *BetModel) Flagged(marketID uuid.UUID) ([]Bet, error) {
+	stmt := `SELECT b.id, b.user_id, u.username, b.amount, b.outcome_id, o.label, b.flagged_at
+		FROM bets b
+		JOIN users u ON u.id = b.user_id
+		JOIN outcomes o ON o.id = b.outcome_id
+		WHERE b.market_id = $1
+		  AND b.flagged_at IS NOT NULL
+		ORDER BY b.created_at, b.id`
+
+	rows, err := m.DB.Query(stmt, marketID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bets []Bet
+
+	for rows.Next() {
+		var b Bet
+		err = rows.Scan(&b.ID, &b.UserID, &b.Username, &b.Amount, &b.OutcomeID, &b.OutcomeLabel, &b.FlaggedAt)
+		if err != nil {
+			return nil, err
+		}
+		bets = append(bets, b)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return bets, nil
+}
@@ -0,0 +1,179 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Comment struct {
+	ID        uuid.UUID
+	MarketID  uuid.UUID
+	UserID    uuid.UUID
+	Username  string
+	ParentID  *uuid.UUID
+	Body      string
+	CreatedAt time.Time
+	EditedAt  *time.Time
+	DeletedAt *time.Time
+	RemovedBy *uuid.UUID
+
+	// The commenter's stake in the market at read time. PositionLabel is
+	// empty when they hold no position.
+	PositionLabel  string
+	PositionAmount int
+}
+
+type CommentModel struct {
+	DB *sql.DB
+}
+
+func (m *CommentModel) Insert(marketID, userID uuid.UUID, parentID *uuid.UUID, body string) (uuid.UUID, error) {
+	stmt := `INSERT INTO comments (market_id, user_id, parent_id, body) VALUES ($1, $2, $3, $4) RETURNING id`
+
+	var id uuid.UUID
+	err := m.DB.QueryRow(stmt, marketID, userID, parentID, body).Scan(&id)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return id, nil
+}
+
+func (m *CommentModel) Get(id uuid.UUID) (Comment, error) {
+	var c Comment
+	stmt := `SELECT c.id, c.market_id, c.user_id, u.username, c.parent_id, c.body, c.created_at, c.edited_at, c.deleted_at, c.removed_by
+		FROM comments c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.id = $1`
+
+	err := m.DB.QueryRow(stmt, id).Scan(
+		&c.ID,
+		&c.MarketID,
+		&c.UserID,
+		&c.Username,
+		&c.ParentID,
+		&c.Body,
+		&c.CreatedAt,
+		&c.EditedAt,
+		&c.DeletedAt,
+		&c.RemovedBy,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Comment{}, ErrNoRecord
+		}
+
+		return Comment{}, err
+	}
+
+	return c, nil
+}
+
+func (m *CommentModel) UpdateBody(id uuid.UUID, body string) error {
+	stmt := `UPDATE comments SET body = $1, edited_at = NOW() WHERE id = $2 AND deleted_at IS NULL`
+	_, err := m.DB.Exec(stmt, body, id)
+	return err
+}
+
+// Delete keeps the row so that replies stay attached to their thread. A nil
+// removedBy marks a deletion by the author.
+func (m *CommentModel) Delete(id uuid.UUID, removedBy *uuid.UUID) error {
+	stmt := `UPDATE comments SET deleted_at = NOW(), removed_by = $1 WHERE id = $2 AND deleted_at IS NULL`
+	_, err := m.DB.Exec(stmt, removedBy, id)
+	return err
+}
+
+func (m *CommentModel) Hide(tx *sql.Tx, id uuid.UUID, moderatorID uuid.UUID) error {
+	stmt := `UPDATE comments SET deleted_at = NOW(), removed_by = $1 WHERE id = $2 AND deleted_at IS NULL`
+	_, err := tx.Exec(stmt, moderatorID, id)
+	return err
+}
+
+func (m *CommentModel) CountThreads(marketID uuid.UUID) (int, error) {
+	var count int
+	stmt := `SELECT COUNT(*) FROM comments WHERE market_id = $1 AND parent_id IS NULL`
+
+	err := m.DB.QueryRow(stmt, marketID).Scan(&count)
+
+	return count, err
+}
+
+// Threads returns a page of top-level comments, newest first, together with
+// every reply below them.
+func (m *CommentModel) Threads(marketID uuid.UUID, limit, offset int) ([]Comment, error) {
+	stmt := `WITH RECURSIVE roots AS (
+		SELECT id
+		FROM comments
+		WHERE market_id = $1 AND parent_id IS NULL
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	), thread AS (
+		SELECT c.* FROM comments c JOIN roots r ON r.id = c.id
+		UNION ALL
+		SELECT c.* FROM comments c JOIN thread t ON c.parent_id = t.id
+	)
+	SELECT
+		t.id,
+		t.market_id,
+		t.user_id,
+		u.username,
+		t.parent_id,
+		t.body,
+		t.created_at,
+		t.edited_at,
+		t.deleted_at,
+		t.removed_by,
+		COALESCE(p.label, ''),
+		COALESCE(p.amount, 0)
+	FROM thread t
+	JOIN users u ON u.id = t.user_id
+	LEFT JOIN LATERAL (
+		SELECT o.label, SUM(b.amount)::int AS amount
+		FROM bets b
+		JOIN outcomes o ON o.id = b.outcome_id
+		WHERE b.market_id = t.market_id AND b.user_id = t.user_id
+		GROUP BY o.label
+		ORDER BY 2 DESC
+		LIMIT 1
+	) p ON true
+	ORDER BY t.created_at`
+
+	rows, err := m.DB.Query(stmt, marketID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var comments []Comment
+
+	for rows.Next() {
+		var c Comment
+		err = rows.Scan(
+			&c.ID,
+			&c.MarketID,
+			&c.UserID,
+			&c.Username,
+			&c.ParentID,
+			&c.Body,
+			&c.CreatedAt,
+			&c.EditedAt,
+			&c.DeletedAt,
+			&c.RemovedBy,
+			&c.PositionLabel,
+			&c.PositionAmount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
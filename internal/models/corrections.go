@@ -0,0 +1,94 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ResolutionCorrection records an admin changing the winning outcome of a
+// market that had already been settled.
+type ResolutionCorrection struct {
+	ID                   uuid.UUID
+	MarketID             uuid.UUID
+	PreviousOutcomeID    uuid.UUID
+	PreviousOutcomeLabel string
+	OutcomeID            uuid.UUID
+	OutcomeLabel         string
+	Reason               string
+	CorrectedBy          uuid.UUID
+	CorrectedByUsername  string
+	CreatedAt            time.Time
+}
+
+type CorrectionModel struct {
+	DB *sql.DB
+}
+
+func (m *CorrectionModel) Insert(tx *sql.Tx, c ResolutionCorrection) (uuid.UUID, error) {
+	stmt := `INSERT INTO resolution_corrections (market_id, previous_outcome_id, outcome_id, reason, corrected_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+
+	var id uuid.UUID
+	err := tx.QueryRow(stmt, c.MarketID, c.PreviousOutcomeID, c.OutcomeID, c.Reason, c.CorrectedBy).Scan(&id)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return id, nil
+}
+
+// InsertPayout records how a correction changed one bet's payout.
+func (m *CorrectionModel) InsertPayout(tx *sql.Tx, correctionID, betID, userID uuid.UUID, previousPayout, payout int) error {
+	stmt := `INSERT INTO payout_corrections (correction_id, bet_id, user_id, previous_payout, payout)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := tx.Exec(stmt, correctionID, betID, userID, previousPayout, payout)
+	return err
+}
+
+func (m *CorrectionModel) ForMarket(marketID uuid.UUID) ([]ResolutionCorrection, error) {
+	stmt := `SELECT c.id, c.market_id, c.previous_outcome_id, po.label, c.outcome_id, o.label, c.reason, c.corrected_by, u.username, c.created_at
+		FROM resolution_corrections c
+		JOIN outcomes po ON po.id = c.previous_outcome_id
+		JOIN outcomes o ON o.id = c.outcome_id
+		JOIN users u ON u.id = c.corrected_by
+		WHERE c.market_id = $1
+		ORDER BY c.created_at DESC`
+
+	rows, err := m.DB.Query(stmt, marketID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var corrections []ResolutionCorrection
+
+	for rows.Next() {
+		var c ResolutionCorrection
+		err = rows.Scan(
+			&c.ID,
+			&c.MarketID,
+			&c.PreviousOutcomeID,
+			&c.PreviousOutcomeLabel,
+			&c.OutcomeID,
+			&c.OutcomeLabel,
+			&c.Reason,
+			&c.CorrectedBy,
+			&c.CorrectedByUsername,
+			&c.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		corrections = append(corrections, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return corrections, nil
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type DisputeStatus string
+
+const (
+	DisputeOpen       DisputeStatus = "open"
+	DisputeUpheld     DisputeStatus = "upheld"
+	DisputeOverturned DisputeStatus = "overturned"
+)
+
+// AllDisputeWindows lists the dispute periods, in hours, a creator can pick.
+// Zero settles the market as soon as it is resolved.
+func AllDisputeWindows() []int {
+	return []int{0, 24, 48, 72}
+}
+
+// Dispute is a bettor's objection to a proposed resolution. UserID is nil,
+// and Username empty, for reviews the platform opened itself, see
+// InsertReview.
+type Dispute struct {
+	ID            uuid.UUID
+	MarketID      uuid.UUID
+	UserID        *uuid.UUID
+	Username      string
+	Justification string
+	Status        DisputeStatus
+	CreatedAt     time.Time
+	DecidedAt     *time.Time
+}
+
+type DisputeModel struct {
+	DB *sql.DB
+}
+
+func (m *DisputeModel) Insert(tx *sql.Tx, marketID, userID uuid.UUID, justification string) error {
+	stmt := `INSERT INTO disputes (market_id, user_id, justification) VALUES ($1, $2, $3)`
+
+	_, err := tx.Exec(stmt, marketID, userID, justification)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrDisputeAlreadyFiled
+		}
+	}
+
+	return err
+}
+
+// InsertReview opens a dispute in the platform's name, putting the market in
+// front of the admins as if a bettor had disputed it.
+func (m *DisputeModel) InsertReview(tx *sql.Tx, marketID uuid.UUID, justification string) error {
+	stmt := `INSERT INTO disputes (market_id, justification) VALUES ($1, $2)`
+	_, err := tx.Exec(stmt, marketID, justification)
+	return err
+}
+
+func (m *DisputeModel) ForMarket(marketID uuid.UUID) ([]Dispute, error) {
+	stmt := `SELECT d.id, d.market_id, d.user_id, COALESCE(u.username, ''), d.justification, d.status, d.created_at, d.decided_at
+		FROM disputes d
+		LEFT JOIN users u ON u.id = d.user_id
+		WHERE d.market_id = $1
+		ORDER BY d.created_at`
+
+	rows, err := m.DB.Query(stmt, marketID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var disputes []Dispute
+
+	for rows.Next() {
+		var d Dispute
+		err = rows.Scan(&d.ID, &d.MarketID, &d.UserID, &d.Username, &d.Justification, &d.Status, &d.CreatedAt, &d.DecidedAt)
+		if err != nil {
+			return nil, err
+		}
+		disputes = append(disputes, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return disputes, nil
+}
+
+// Decide closes every open dispute on the market with the admin's ruling.
+func (m *DisputeModel) Decide(tx *sql.Tx, marketID uuid.UUID, status DisputeStatus, adminID uuid.UUID) error {
+	stmt := `UPDATE disputes
+		SET status = $1, decided_at = NOW(), decided_by = $2
+		WHERE market_id = $3 AND status = 'open'`
+
+	_, err := tx.Exec(stmt, status, adminID, marketID)
+	return err
+}
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event groups related markets under one page, e.g. every question about a
+// tournament. Markets counts only its public, visible markets.
+type Event struct {
+	ID          uuid.UUID
+	Title       string
+	Description string
+	StartsOn    time.Time
+	CreatedBy   uuid.UUID
+	CreatedAt   time.Time
+	Markets     int
+
+	CreatorUsername string
+}
+
+type EventModel struct {
+	DB *sql.DB
+}
+
+const eventColumns = `e.id, e.title, e.description, e.starts_on, e.created_by, e.created_at,
+	(SELECT COUNT(*) FROM markets m WHERE m.event_id = e.id AND m.group_id IS NULL AND m.hidden_at IS NULL AND m.status <> 'pending'),
+	u.username`
+
+func scanEvent(row interface{ Scan(...any) error }, e *Event) error {
+	return row.Scan(
+		&e.ID,
+		&e.Title,
+		&e.Description,
+		&e.StartsOn,
+		&e.CreatedBy,
+		&e.CreatedAt,
+		&e.Markets,
+		&e.CreatorUsername,
+	)
+}
+
+func (m *EventModel) Insert(title, description string, startsOn time.Time, createdBy uuid.UUID) (uuid.UUID, error) {
+	stmt := `INSERT INTO events (title, description, starts_on, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`
+
+	var id uuid.UUID
+	err := m.DB.QueryRow(stmt, title, description, startsOn, createdBy).Scan(&id)
+	return id, err
+}
+
+func (m *EventModel) Get(id uuid.UUID) (Event, error) {
+	stmt := `SELECT ` + eventColumns + `
+	FROM events e
+	JOIN users u ON u.id = e.created_by
+	WHERE e.id = $1`
+
+	var e Event
+	err := scanEvent(m.DB.QueryRow(stmt, id), &e)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Event{}, ErrNoRecord
+		}
+		return Event{}, err
+	}
+
+	return e, nil
+}
+
+// Upcoming lists the events taking place today or later, soonest first.
+func (m *EventModel) Upcoming() ([]Event, error) {
+	return m.query(`SELECT ` + eventColumns + `
+	FROM events e
+	JOIN users u ON u.id = e.created_by
+	WHERE e.starts_on >= CURRENT_DATE
+	ORDER BY e.starts_on, e.created_at
+	LIMIT 50`)
+}
+
+// Past lists the latest events that have already taken place.
+func (m *EventModel) Past() ([]Event, error) {
+	return m.query(`SELECT ` + eventColumns + `
+	FROM events e
+	JOIN users u ON u.id = e.created_by
+	WHERE e.starts_on < CURRENT_DATE
+	ORDER BY e.starts_on DESC, e.created_at DESC
+	LIMIT 20`)
+}
+
+func (m *EventModel) query(stmt string, args ...any) ([]Event, error) {
+	rows, err := m.DB.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []Event
+
+	for rows.Next() {
+		var e Event
+		err = scanEvent(rows, &e)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
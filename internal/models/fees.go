@@ -0,0 +1,201 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxFeeBps caps each fee at 10% of a market's pool.
+const MaxFeeBps = 1000
+
+type FeeKind string
+
+const (
+	FeePlatform FeeKind = "platform"
+	FeeCreator  FeeKind = "creator"
+)
+
+// FeeSchedule is copied onto every new market. PlatformFeeBps goes to the
+// treasury and CreatorFeeBps to the market's creator.
+type FeeSchedule struct {
+	PlatformFeeBps int
+	CreatorFeeBps  int
+}
+
+// FeeEntry is a fee taken when a market settled. Entries are never updated:
+// a correction records a negative entry for each fee it gives back. UserID
+// is nil for platform fees, which are credited to the treasury.
+type FeeEntry struct {
+	ID          uuid.UUID
+	MarketID    uuid.UUID
+	MarketTitle string
+	Kind        FeeKind
+	UserID      *uuid.UUID
+	Amount      int
+	CreatedAt   time.Time
+}
+
+type FeeModel struct {
+	DB *sql.DB
+}
+
+func (m *FeeModel) Schedule() (FeeSchedule, error) {
+	var fs FeeSchedule
+	stmt := `SELECT platform_fee_bps, creator_fee_bps FROM fee_schedule`
+
+	err := m.DB.QueryRow(stmt).Scan(&fs.PlatformFeeBps, &fs.CreatorFeeBps)
+	if err != nil {
+		return FeeSchedule{}, err
+	}
+
+	return fs, nil
+}
+
+func (m *FeeModel) UpdateSchedule(fs FeeSchedule, adminID uuid.UUID) error {
+	stmt := `UPDATE fee_schedule
+		SET platform_fee_bps = $1,
+		    creator_fee_bps = $2,
+		    updated_by = $3,
+		    updated_at = NOW()`
+
+	_, err := m.DB.Exec(stmt, fs.PlatformFeeBps, fs.CreatorFeeBps, adminID)
+	return err
+}
+
+func (m *FeeModel) Insert(tx *sql.Tx, marketID uuid.UUID, kind FeeKind, userID *uuid.UUID, amount int) error {
+	stmt := `INSERT INTO fee_entries (market_id, kind, user_id, amount) VALUES ($1, $2, $3, $4)`
+
+	_, err := tx.Exec(stmt, marketID, kind, userID, amount)
+	return err
+}
+
+// NetForMarket sums the market's fee entries per kind and recipient, leaving
+// out those that have already been given back.
+func (m *FeeModel) NetForMarket(tx *sql.Tx, marketID uuid.UUID) ([]FeeEntry, error) {
+	stmt := `SELECT kind, user_id, SUM(amount)
+		FROM fee_entries
+		WHERE market_id = $1
+		GROUP BY kind, user_id
+		HAVING SUM(amount) <> 0`
+
+	rows, err := tx.Query(stmt, marketID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []FeeEntry
+
+	for rows.Next() {
+		e := FeeEntry{MarketID: marketID}
+		err = rows.Scan(&e.Kind, &e.UserID, &e.Amount)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ForUser lists the creator fees paid to userID, newest first.
+func (m *FeeModel) ForUser(userID uuid.UUID) ([]FeeEntry, error) {
+	stmt := `SELECT f.id, f.market_id, mk.title, f.kind, f.user_id, f.amount, f.created_at
+		FROM fee_entries f
+		JOIN markets mk ON mk.id = f.market_id
+		WHERE f.user_id = $1
+		ORDER BY f.created_at DESC
+		LIMIT 50`
+
+	rows, err := m.DB.Query(stmt, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []FeeEntry
+
+	for rows.Next() {
+		var e FeeEntry
+		err = rows.Scan(&e.ID, &e.MarketID, &e.MarketTitle, &e.Kind, &e.UserID, &e.Amount, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// treasuryStmt adds up platform fees and the net result of the liquidity the
+// treasury has seeded. Seeds that have not settled yet count as spent.
+const treasuryStmt = `SELECT
+	(SELECT COALESCE(SUM(amount), 0) FROM fee_entries WHERE kind = 'platform')
+	+ (SELECT COALESCE(SUM(COALESCE(payout_amount, 0) - amount), 0) FROM liquidity_positions WHERE user_id IS NULL)`
+
+// Treasury is the balance of the platform's own account.
+func (m *FeeModel) Treasury() (int, error) {
+	var balance int
+
+	err := m.DB.QueryRow(treasuryStmt).Scan(&balance)
+
+	return balance, err
+}
+
+// TreasuryForUpdate reads the treasury balance inside tx before spending
+// from it. The treasury has no row of its own, so the fee schedule row is
+// locked to keep two spends from both seeing the same balance.
+func (m *FeeModel) TreasuryForUpdate(tx *sql.Tx) (int, error) {
+	_, err := tx.Exec(`SELECT 1 FROM fee_schedule FOR UPDATE`)
+	if err != nil {
+		return 0, err
+	}
+
+	var balance int
+
+	err = tx.QueryRow(treasuryStmt).Scan(&balance)
+
+	return balance, err
+}
+
+// Latest lists the most recent fee entries across all markets.
+func (m *FeeModel) Latest() ([]FeeEntry, error) {
+	stmt := `SELECT f.id, f.market_id, mk.title, f.kind, f.user_id, f.amount, f.created_at
+		FROM fee_entries f
+		JOIN markets mk ON mk.id = f.market_id
+		ORDER BY f.created_at DESC
+		LIMIT 50`
+
+	rows, err := m.DB.Query(stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []FeeEntry
+
+	for rows.Next() {
+		var e FeeEntry
+		err = rows.Scan(&e.ID, &e.MarketID, &e.MarketTitle, &e.Kind, &e.UserID, &e.Amount, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,405 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GroupRole string
+
+const (
+	GroupOwner  GroupRole = "owner"
+	GroupAdmin  GroupRole = "admin"
+	GroupMember GroupRole = "member"
+)
+
+func AllGroupRoles() []GroupRole {
+	return []GroupRole{
+		GroupOwner,
+		GroupAdmin,
+		GroupMember,
+	}
+}
+
+// CanManage reports whether the role may invite people and change the roles
+// of other members.
+func (r GroupRole) CanManage() bool {
+	return r == GroupOwner || r == GroupAdmin
+}
+
+type Group struct {
+	ID          uuid.UUID
+	Name        string
+	Description string
+	OwnerID     uuid.UUID
+	Members     int
+	Role        GroupRole
+	CreatedAt   time.Time
+}
+
+type GroupMembership struct {
+	UserID   uuid.UUID
+	Username string
+	Role     GroupRole
+	JoinedAt time.Time
+}
+
+type GroupInvite struct {
+	ID        uuid.UUID
+	Token     string
+	GroupID   uuid.UUID
+	GroupName string
+	CreatedBy uuid.UUID
+	ExpiresAt time.Time
+	MaxUses   *int
+	Uses      int
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// Usable reports whether the invite can still be redeemed at now.
+func (i GroupInvite) Usable(now time.Time) bool {
+	if i.RevokedAt != nil || !now.Before(i.ExpiresAt) {
+		return false
+	}
+
+	return i.MaxUses == nil || i.Uses < *i.MaxUses
+}
+
+type GroupStanding struct {
+	Rank      int
+	UserID    uuid.UUID
+	Username  string
+	Markets   int
+	Staked    int
+	NetProfit int
+}
+
+type GroupModel struct {
+	DB *sql.DB
+}
+
+func (m *GroupModel) Insert(tx *sql.Tx, name, description string, ownerID uuid.UUID) (uuid.UUID, error) {
+	stmt := `INSERT INTO groups (name, description, owner_id) VALUES ($1, $2, $3) RETURNING id`
+
+	var id uuid.UUID
+	err := tx.QueryRow(stmt, name, description, ownerID).Scan(&id)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return id, nil
+}
+
+// AddMember is a no-op for users who already belong to the group, so
+// redeeming a second invite never downgrades an existing role.
+func (m *GroupModel) AddMember(tx *sql.Tx, groupID, userID uuid.UUID, role GroupRole) (bool, error) {
+	stmt := `INSERT INTO group_members (group_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (group_id, user_id) DO NOTHING`
+
+	res, err := tx.Exec(stmt, groupID, userID, role)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func (m *GroupModel) SetRole(groupID, userID uuid.UUID, role GroupRole) error {
+	stmt := `UPDATE group_members SET role = $1 WHERE group_id = $2 AND user_id = $3`
+
+	res, err := m.DB.Exec(stmt, role, groupID, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrNoRecord
+	}
+
+	return nil
+}
+
+// MemberRole returns ErrNoRecord when the user does not belong to the group.
+func (m *GroupModel) MemberRole(groupID, userID uuid.UUID) (GroupRole, error) {
+	var role GroupRole
+	stmt := `SELECT role FROM group_members WHERE group_id = $1 AND user_id = $2`
+
+	err := m.DB.QueryRow(stmt, groupID, userID).Scan(&role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNoRecord
+		}
+
+		return "", err
+	}
+
+	return role, nil
+}
+
+func (m *GroupModel) Get(id uuid.UUID) (Group, error) {
+	var g Group
+	stmt := `SELECT
+		g.id,
+		g.name,
+		g.description,
+		g.owner_id,
+		(SELECT COUNT(*) FROM group_members gm WHERE gm.group_id = g.id),
+		g.created_at
+	FROM groups g
+	WHERE g.id = $1`
+
+	err := m.DB.QueryRow(stmt, id).Scan(&g.ID, &g.Name, &g.Description, &g.OwnerID, &g.Members, &g.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Group{}, ErrNoRecord
+		}
+
+		return Group{}, err
+	}
+
+	return g, nil
+}
+
+func (m *GroupModel) ForUser(userID uuid.UUID) ([]Group, error) {
+	stmt := `SELECT
+		g.id,
+		g.name,
+		g.description,
+		g.owner_id,
+		(SELECT COUNT(*) FROM group_members c WHERE c.group_id = g.id),
+		gm.role,
+		g.created_at
+	FROM group_members gm
+	JOIN groups g ON g.id = gm.group_id
+	WHERE gm.user_id = $1
+	ORDER BY g.name`
+
+	rows, err := m.DB.Query(stmt, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var groups []Group
+
+	for rows.Next() {
+		var g Group
+		err = rows.Scan(&g.ID, &g.Name, &g.Description, &g.OwnerID, &g.Members, &g.Role, &g.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (m *GroupModel) Members(groupID uuid.UUID) ([]GroupMembership, error) {
+	stmt := `SELECT gm.user_id, u.username, gm.role, gm.joined_at
+		FROM group_members gm
+		JOIN users u ON u.id = gm.user_id
+		WHERE gm.group_id = $1
+		ORDER BY gm.joined_at`
+
+	rows, err := m.DB.Query(stmt, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []GroupMembership
+
+	for rows.Next() {
+		var gm GroupMembership
+		err = rows.Scan(&gm.UserID, &gm.Username, &gm.Role, &gm.JoinedAt)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, gm)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (m *GroupModel) InsertInvite(groupID, createdBy uuid.UUID, token string, expiresAt time.Time, maxUses *int) error {
+	stmt := `INSERT INTO group_invites (token, group_id, created_by, expires_at, max_uses)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := m.DB.Exec(stmt, token, groupID, createdBy, expiresAt, maxUses)
+	return err
+}
+
+func (m *GroupModel) RevokeInvite(groupID, inviteID uuid.UUID) error {
+	stmt := `UPDATE group_invites SET revoked_at = NOW()
+		WHERE id = $1 AND group_id = $2 AND revoked_at IS NULL`
+
+	_, err := m.DB.Exec(stmt, inviteID, groupID)
+	return err
+}
+
+const inviteColumns = `i.id, i.token, i.group_id, g.name, i.created_by, i.expires_at, i.max_uses, i.uses, i.revoked_at, i.created_at`
+
+func scanInvite(row interface{ Scan(...any) error }) (GroupInvite, error) {
+	var i GroupInvite
+	err := row.Scan(
+		&i.ID,
+		&i.Token,
+		&i.GroupID,
+		&i.GroupName,
+		&i.CreatedBy,
+		&i.ExpiresAt,
+		&i.MaxUses,
+		&i.Uses,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+func (m *GroupModel) InviteByToken(token string) (GroupInvite, error) {
+	stmt := `SELECT ` + inviteColumns + `
+		FROM group_invites i
+		JOIN groups g ON g.id = i.group_id
+		WHERE i.token = $1`
+
+	invite, err := scanInvite(m.DB.QueryRow(stmt, token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return GroupInvite{}, ErrNoRecord
+		}
+
+		return GroupInvite{}, err
+	}
+
+	return invite, nil
+}
+
+// InviteForUpdate locks the invite so concurrent redemptions cannot exceed
+// its use limit.
+func (m *GroupModel) InviteForUpdate(tx *sql.Tx, token string) (GroupInvite, error) {
+	stmt := `SELECT ` + inviteColumns + `
+		FROM group_invites i
+		JOIN groups g ON g.id = i.group_id
+		WHERE i.token = $1
+		FOR UPDATE OF i`
+
+	invite, err := scanInvite(tx.QueryRow(stmt, token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return GroupInvite{}, ErrNoRecord
+		}
+
+		return GroupInvite{}, err
+	}
+
+	return invite, nil
+}
+
+func (m *GroupModel) UseInvite(tx *sql.Tx, inviteID uuid.UUID) error {
+	stmt := `UPDATE group_invites SET uses = uses + 1 WHERE id = $1`
+	_, err := tx.Exec(stmt, inviteID)
+	return err
+}
+
+func (m *GroupModel) Invites(groupID uuid.UUID) ([]GroupInvite, error) {
+	stmt := `SELECT ` + inviteColumns + `
+		FROM group_invites i
+		JOIN groups g ON g.id = i.group_id
+		WHERE i.group_id = $1
+		  AND i.revoked_at IS NULL
+		  AND i.expires_at > NOW()
+		  AND (i.max_uses IS NULL OR i.uses < i.max_uses)
+		ORDER BY i.created_at DESC`
+
+	rows, err := m.DB.Query(stmt, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invites []GroupInvite
+
+	for rows.Next() {
+		invite, err := scanInvite(rows)
+		if err != nil {
+			return nil, err
+		}
+		invites = append(invites, invite)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return invites, nil
+}
+
+// Leaderboard ranks current members by net profit on the group's settled
+// markets. It is computed on read because groups are small.
+func (m *GroupModel) Leaderboard(groupID uuid.UUID) ([]GroupStanding, error) {
+	stmt := `SELECT
+		ROW_NUMBER() OVER (ORDER BY COALESCE(SUM(b.payout_amount - b.amount), 0) DESC, u.username),
+		u.id,
+		u.username,
+		COUNT(DISTINCT b.market_id),
+		COALESCE(SUM(b.amount), 0),
+		COALESCE(SUM(b.payout_amount - b.amount), 0)
+	FROM group_members gm
+	JOIN users u ON u.id = gm.user_id
+	LEFT JOIN (
+		SELECT b.user_id, b.market_id, b.amount, b.payout_amount
+		FROM bets b
+		JOIN markets m ON m.id = b.market_id
+		WHERE m.group_id = $1
+		  AND b.payout_amount IS NOT NULL
+	) b ON b.user_id = gm.user_id
+	WHERE gm.group_id = $1
+	GROUP BY u.id, u.username
+	ORDER BY 1`
+
+	rows, err := m.DB.Query(stmt, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var standings []GroupStanding
+
+	for rows.Next() {
+		var s GroupStanding
+		err = rows.Scan(&s.Rank, &s.UserID, &s.Username, &s.Markets, &s.Staked, &s.NetProfit)
+		if err != nil {
+			return nil, err
+		}
+		standings = append(standings, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return standings, nil
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+// IntegrityRule decides what happens when a market's resolver has a stake in
+// it. Like fees, the rule in force is copied onto every new market.
+type IntegrityRule string
+
+const (
+	IntegrityNone IntegrityRule = "none"
+
+	// IntegrityBlock keeps resolvers from betting on their markets.
+	IntegrityBlock IntegrityRule = "block"
+
+	// IntegrityFlag lets resolvers bet, but their bets are flagged and shown
+	// on the market page.
+	IntegrityFlag IntegrityRule = "flag"
+
+	// IntegrityReview sends the resolution of a market its resolver bet on
+	// to the admins instead of paying it out.
+	IntegrityReview IntegrityRule = "review"
+)
+
+func AllIntegrityRules() []IntegrityRule {
+	return []IntegrityRule{
+		IntegrityNone,
+		IntegrityBlock,
+		IntegrityFlag,
+		IntegrityReview,
+	}
+}
+
+type IntegrityModel struct {
+	DB *sql.DB
+}
+
+func (m *IntegrityModel) Rule() (IntegrityRule, error) {
+	var rule IntegrityRule
+	stmt := `SELECT rule FROM integrity_policy`
+
+	err := m.DB.QueryRow(stmt).Scan(&rule)
+	if err != nil {
+		return "", err
+	}
+
+	return rule, nil
+}
+
+func (m *IntegrityModel) UpdateRule(rule IntegrityRule, adminID uuid.UUID) error {
+	stmt := `UPDATE integrity_policy
+		SET rule = $1,
+		    updated_by = $2,
+		    updated_at = NOW()`
+
+	_, err := m.DB.Exec(stmt, rule, adminID)
+	return err
+}
@@ -0,0 +1,212 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LeaderboardSort string
+
+const (
+	SortByProfit   LeaderboardSort = "profit"
+	SortByROI      LeaderboardSort = "roi"
+	SortByAccuracy LeaderboardSort = "accuracy"
+)
+
+func AllLeaderboardSorts() []LeaderboardSort {
+	return []LeaderboardSort{
+		SortByProfit,
+		SortByROI,
+		SortByAccuracy,
+	}
+}
+
+const PeriodAllTime = "all"
+
+// Ratios are noisy for users with a handful of bets, so ROI and accuracy
+// rankings only include users with at least this many settled markets.
+const minMarketsForRatios = 3
+
+type LeaderboardEntry struct {
+	Rank       int
+	UserID     uuid.UUID
+	Username   string
+	Markets    int
+	Staked     int
+	NetProfit  int
+	ROI        float64
+	BrierScore *float64
+	ComputedAt time.Time
+}
+
+type LeaderboardModel struct {
+	DB *sql.DB
+}
+
+// Refresh rebuilds every ranking from settled bets in one transaction so
+// readers never observe a half-written leaderboard. Periods are either
+// "all" or a calendar month formatted as YYYY-MM, and the empty category
+// holds the cross-category ranking.
+//
+// Brier scores compare each bet's implied probability with the share of
+// the market its outcome resolved to, not with whether it paid out: split
+// and scalar resolutions pay outcomes that only partly happened, and
+// rounding can leave a small bet on the winner with nothing. Markets settled
+// before shares were recorded count their winning outcome as the whole.
+func (m *LeaderboardModel) Refresh() error {
+	tx, err := m.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM leaderboard_entries`)
+	if err != nil {
+		return err
+	}
+
+	stmt := `INSERT INTO leaderboard_entries
+		(period, category, user_id, markets, staked, net_profit, roi, brier_score, computed_at)
+	SELECT
+		COALESCE(s.period, 'all'),
+		COALESCE(s.category, ''),
+		s.user_id,
+		COUNT(DISTINCT s.market_id),
+		SUM(s.amount),
+		SUM(s.payout_amount - s.amount),
+		COALESCE(SUM(s.payout_amount - s.amount)::float8 / NULLIF(SUM(s.amount), 0), 0),
+		AVG(power(s.implied_probability - s.won, 2)),
+		NOW()
+	FROM (
+		SELECT
+			b.user_id,
+			b.market_id,
+			b.amount,
+			b.payout_amount,
+			b.implied_probability,
+			CASE
+				WHEN EXISTS (SELECT 1 FROM outcomes r WHERE r.market_id = b.market_id AND r.resolved_share_bps IS NOT NULL)
+					THEN COALESCE(o.resolved_share_bps, 0) / 10000.0
+				WHEN b.outcome_id = m.resolved_outcome_id THEN 1.0
+				ELSE 0.0
+			END AS won,
+			to_char(b.settled_at, 'YYYY-MM') AS period,
+			m.category
+		FROM bets b
+		JOIN markets m ON m.id = b.market_id
+		JOIN outcomes o ON o.id = b.outcome_id
+		WHERE b.payout_amount IS NOT NULL
+		  AND b.settled_at IS NOT NULL
+	) s
+	GROUP BY GROUPING SETS (
+		(s.user_id),
+		(s.user_id, s.period),
+		(s.user_id, s.category),
+		(s.user_id, s.period, s.category)
+	)`
+
+	_, err = tx.Exec(stmt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (m *LeaderboardModel) Ranking(period string, category string, sort LeaderboardSort, limit int) ([]LeaderboardEntry, error) {
+	order := "l.net_profit DESC"
+	filter := ""
+	minMarkets := 0
+
+	switch sort {
+	case SortByROI:
+		order = "l.roi DESC"
+		minMarkets = minMarketsForRatios
+	case SortByAccuracy:
+		order = "l.brier_score ASC"
+		filter = "AND l.brier_score IS NOT NULL"
+		minMarkets = minMarketsForRatios
+	}
+
+	stmt := fmt.Sprintf(`SELECT
+		ROW_NUMBER() OVER (ORDER BY %s, l.markets DESC, u.username),
+		l.user_id,
+		u.username,
+		l.markets,
+		l.staked,
+		l.net_profit,
+		l.roi,
+		l.brier_score,
+		l.computed_at
+	FROM leaderboard_entries l
+	JOIN users u ON u.id = l.user_id
+	WHERE l.period = $1
+	  AND l.category = $2
+	  AND l.markets >= $3
+	  %s
+	ORDER BY 1
+	LIMIT $4`, order, filter)
+
+	rows, err := m.DB.Query(stmt, period, category, minMarkets, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []LeaderboardEntry
+
+	for rows.Next() {
+		var e LeaderboardEntry
+		err = rows.Scan(
+			&e.Rank,
+			&e.UserID,
+			&e.Username,
+			&e.Markets,
+			&e.Staked,
+			&e.NetProfit,
+			&e.ROI,
+			&e.BrierScore,
+			&e.ComputedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (m *LeaderboardModel) Months() ([]string, error) {
+	stmt := `SELECT DISTINCT period FROM leaderboard_entries WHERE period <> 'all' ORDER BY period DESC`
+
+	rows, err := m.DB.Query(stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var months []string
+
+	for rows.Next() {
+		var month string
+		err = rows.Scan(&month)
+		if err != nil {
+			return nil, err
+		}
+		months = append(months, month)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return months, nil
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LiquidityPosition is a stake seeded into an outcome pool when a market is
+// created. It is settled like a bet but is not one: it does not count
+// towards bet history, achievements or the one-bet-per-market rule. UserID
+// is nil when the treasury paid for it.
+type LiquidityPosition struct {
+	ID        uuid.UUID
+	MarketID  uuid.UUID
+	OutcomeID uuid.UUID
+	UserID    *uuid.UUID
+	Amount    int
+	Payout    *int
+	CreatedAt time.Time
+
+	// SeasonClosed is set when the position was seeded in a season that
+	// has since been archived, see BetModel.
+	SeasonClosed bool
+
+	OutcomeLabel string
+	Username     *string
+}
+
+type LiquidityModel struct {
+	DB *sql.DB
+}
+
+func (m *LiquidityModel) Insert(tx *sql.Tx, marketID, outcomeID uuid.UUID, userID *uuid.UUID, amount int, seasonID *uuid.UUID) error {
+	stmt := `INSERT INTO liquidity_positions (market_id, outcome_id, user_id, amount, season_id)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := tx.Exec(stmt, marketID, outcomeID, userID, amount, seasonID)
+	return err
+}
+
+// Exists reports whether userID seeded liquidity into the market.
+func (m *LiquidityModel) Exists(marketID, userID uuid.UUID) (bool, error) {
+	var exists bool
+	stmt := `SELECT EXISTS(SELECT true FROM liquidity_positions WHERE market_id = $1 AND user_id = $2)`
+
+	err := m.DB.QueryRow(stmt, marketID, userID).Scan(&exists)
+
+	return exists, err
+}
+
+// ForMarket lists the market's positions in the order they were seeded.
+func (m *LiquidityModel) ForMarket(marketID uuid.UUID) ([]LiquidityPosition, error) {
+	stmt := `SELECT l.id, l.market_id, l.outcome_id, l.user_id, l.amount, l.payout_amount, l.created_at, o.label, u.username
+		FROM liquidity_positions l
+		JOIN outcomes o ON o.id = l.outcome_id
+		LEFT JOIN users u ON u.id = l.user_id
+		WHERE l.market_id = $1
+		ORDER BY l.created_at, l.id`
+
+	rows, err := m.DB.Query(stmt, marketID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var positions []LiquidityPosition
+
+	for rows.Next() {
+		var p LiquidityPosition
+		err = rows.Scan(&p.ID, &p.MarketID, &p.OutcomeID, &p.UserID, &p.Amount, &p.Payout, &p.CreatedAt, &p.OutcomeLabel, &p.Username)
+		if err != nil {
+			return nil, err
+		}
+		positions = append(positions, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return positions, nil
+}
+
+func (m *LiquidityModel) ForMarketForUpdate(tx *sql.Tx, marketID uuid.UUID) ([]LiquidityPosition, error) {
+	stmt := `SELECT l.id, l.market_id, l.outcome_id, l.user_id, l.amount, l.payout_amount, COALESCE(s.status = 'archived', false)
+		FROM liquidity_positions l
+		LEFT JOIN seasons s ON s.id = l.season_id
+		WHERE l.market_id = $1
+		ORDER BY l.created_at, l.id
+		FOR UPDATE OF l`
+
+	rows, err := tx.Query(stmt, marketID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var positions []LiquidityPosition
+
+	for rows.Next() {
+		var p LiquidityPosition
+		err = rows.Scan(&p.ID, &p.MarketID, &p.OutcomeID, &p.UserID, &p.Amount, &p.Payout, &p.SeasonClosed)
+		if err != nil {
+			return nil, err
+		}
+		positions = append(positions, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return positions, nil
+}
+
+func (m *LiquidityModel) SetPayout(tx *sql.Tx, id uuid.UUID, payout int) error {
+	stmt := `UPDATE liquidity_positions SET payout_amount = $1 WHERE id = $2`
+	_, err := tx.Exec(stmt, payout, id)
+	return err
+}
+
+// Refund returns a position's stake when its market is voided.
+func (m *LiquidityModel) Refund(tx *sql.Tx, id uuid.UUID) error {
+	stmt := `UPDATE liquidity_positions SET payout_amount = amount, refunded_at = NOW() WHERE id = $1`
+	_, err := tx.Exec(stmt, id)
+	return err
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,8 +20,48 @@ type ResolverType string
 const (
 	ResolverCreator ResolverType = "creator"
 	ResolverAdmin   ResolverType = "admin"
+
+	// Oracle markets are resolved from a data source, see Oracle, and fall
+	// back to the admins when it cannot be read.
+	ResolverOracle ResolverType = "oracle"
+
+	// Designated markets are resolved by a user the creator picks. They
+	// stay pending, closed to bets, until that user accepts.
+	ResolverDesignated ResolverType = "designated"
+)
+
+// MarketKind decides how a market is answered. Binary markets resolve to
+// yes or no, scalar markets to a number between their bounds, and bucket and
+// date markets to the bucket their number or date falls in.
+type MarketKind string
+
+const (
+	KindBinary MarketKind = "binary"
+	KindScalar MarketKind = "scalar"
+	KindBucket MarketKind = "bucket"
+	KindDate   MarketKind = "date"
 )
 
+func AllMarketKinds() []MarketKind {
+	return []MarketKind{
+		KindBinary,
+		KindScalar,
+		KindBucket,
+		KindDate,
+	}
+}
+
+// Bucketed reports whether the market's outcomes are generated buckets.
+func (k MarketKind) Bucketed() bool {
+	return k == KindBucket || k == KindDate
+}
+
+// ResolvedByValue reports whether the market is answered with a number
+// rather than by picking outcomes.
+func (k MarketKind) ResolvedByValue() bool {
+	return k == KindScalar || k.Bucketed()
+}
+
 func AllCategories() []Category {
 	return []Category{
 		CategoryFriends,
@@ -32,6 +73,8 @@ func AllResolverTypes() []ResolverType {
 	return []ResolverType{
 		ResolverCreator,
 		ResolverAdmin,
+		ResolverOracle,
+		ResolverDesignated,
 	}
 }
 
@@ -49,38 +92,106 @@ type Market struct {
 	ResolvedOutcomeID *uuid.UUID
 	ResolvedAt        *time.Time
 	ResolvedBy        *uuid.UUID
+	GroupID           *uuid.UUID
+	HiddenAt          *time.Time
+
+	// A market with a dispute window is first resolved to a proposed
+	// outcome and only settles once FinalizesAt passes undisputed.
+	DisputeWindowHours int
+	ProposedOutcomeID  *uuid.UUID
+	ProposedBy         *uuid.UUID
+	ProposedAt         *time.Time
+	FinalizesAt        *time.Time
+
+	// Fees are taken from the pool at settlement, see FeeSchedule.
+	PlatformFeeBps int
+	CreatorFeeBps  int
+
+	// IntegrityRule applies when the resolver has a stake in the market.
+	IntegrityRule IntegrityRule
+
+	// Liquidity is the total seeded into the outcome pools at creation.
+	Liquidity int
+
+	// Scalar markets pay their long and short outcomes by where
+	// ResolvedValue falls between ScalarLower and ScalarUpper, measured on a
+	// log scale when ScalarLog is set.
+	Kind          MarketKind
+	ScalarLower   *float64
+	ScalarUpper   *float64
+	ScalarLog     bool
+	ResolvedValue *float64
+
+	// Bucket and date markets cover BucketLower to BucketUpper in steps of
+	// BucketSize, see Outcome.LowerBound. Dates are Unix timestamps.
+	BucketLower *float64
+	BucketUpper *float64
+	BucketSize  *float64
+
+	// A conditional market is voided unless the market it depends on
+	// resolves to ConditionOutcomeID.
+	ConditionMarketID  *uuid.UUID
+	ConditionOutcomeID *uuid.UUID
+
+	// SeriesID is the series the market was created for, see Series.
+	SeriesID *uuid.UUID
+
+	// EventID is the event the market is listed under. Markets of an event
+	// with the same EventGroup are mutually exclusive.
+	EventID    *uuid.UUID
+	EventGroup *string
+
+	CreatorUsername    string
+	ResolverUsername   *string
+	ResolvedByUsername *string
+	ProposedByUsername *string
+	GroupName          *string
+
+	ConditionMarketTitle  *string
+	ConditionMarketStatus *string
+	ConditionOutcomeLabel *string
+
+	EventTitle *string
 }
 
 type MarketModel struct {
 	DB *sql.DB
 }
 
-func (m *MarketModel) Insert(
-	tx *sql.Tx,
-	title string,
-	description string,
-	category Category,
-	resolverType ResolverType,
-	resolverRef *uuid.UUID,
-	expiresAt time.Time,
-	userID uuid.UUID,
-) (uuid.UUID, error) {
+func (m *MarketModel) Insert(tx *sql.Tx, market Market) (uuid.UUID, error) {
 	stmt := `INSERT INTO markets
-		(title, description, category, resolver_type, resolver_ref, expires_at, status, created_by)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		(title, description, category, resolver_type, resolver_ref, expires_at, status, created_by, group_id, dispute_window_hours, platform_fee_bps, creator_fee_bps, kind, scalar_lower, scalar_upper, scalar_log, bucket_lower, bucket_upper, bucket_size, condition_market_id, condition_outcome_id, series_id, event_id, event_group, integrity_rule)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25)
 		RETURNING id`
 
 	var id uuid.UUID
 	err := tx.QueryRow(
 		stmt,
-		title,
-		description,
-		category,
-		resolverType,
-		resolverRef,
-		expiresAt,
-		"open",
-		userID,
+		market.Title,
+		market.Description,
+		market.Category,
+		market.ResolverType,
+		market.ResolverRef,
+		market.ExpiresAt,
+		market.Status,
+		market.CreatedBy,
+		market.GroupID,
+		market.DisputeWindowHours,
+		market.PlatformFeeBps,
+		market.CreatorFeeBps,
+		market.Kind,
+		market.ScalarLower,
+		market.ScalarUpper,
+		market.ScalarLog,
+		market.BucketLower,
+		market.BucketUpper,
+		market.BucketSize,
+		market.ConditionMarketID,
+		market.ConditionOutcomeID,
+		market.SeriesID,
+		market.EventID,
+		market.EventGroup,
+		market.IntegrityRule,
 	).Scan(&id)
 
 	if err != nil {
@@ -90,7 +201,11 @@ func (m *MarketModel) Insert(
 	return id, nil
 }
 
-func (m *MarketModel) Latest() ([]*Market, error) {
+// Latest returns open markets visible to viewerID, only those of eventID
+// unless it is nil. Markets still waiting on their resolver are left out.
+// Group markets are only listed for members of the group; pass uuid.Nil for
+// anonymous visitors.
+func (m *MarketModel) Latest(viewerID uuid.UUID, eventID *uuid.UUID) ([]*Market, error) {
 	stmt := `SELECT
 		id,
 		title,
@@ -103,13 +218,112 @@ func (m *MarketModel) Latest() ([]*Market, error) {
 		created_by,
 		resolved_outcome_id,
 		resolved_at,
-		resolved_by
+		resolved_by,
+		group_id,
+		kind,
+		event_id,
+		event_group
 	FROM markets
 	WHERE expires_at > NOW()
+	  AND status <> 'pending'
+	  AND hidden_at IS NULL
+	  AND (group_id IS NULL OR group_id IN (SELECT group_id FROM group_members WHERE user_id = $1))
+	  AND ($2::uuid IS NULL OR event_id = $2)
 	ORDER BY expires_at DESC
 	LIMIT 10`
 
-	rows, err := m.DB.Query(stmt)
+	return m.list(stmt, viewerID, eventID)
+}
+
+func (m *MarketModel) ForGroup(groupID uuid.UUID) ([]*Market, error) {
+	stmt := `SELECT
+		id,
+		title,
+		description,
+		category,
+		resolver_type,
+		resolver_ref,
+		expires_at,
+		status,
+		created_by,
+		resolved_outcome_id,
+		resolved_at,
+		resolved_by,
+		group_id,
+		kind,
+		event_id,
+		event_group
+	FROM markets
+	WHERE group_id = $1
+	  AND hidden_at IS NULL
+	ORDER BY created_at DESC
+	LIMIT 50`
+
+	return m.list(stmt, groupID)
+}
+
+// ForSeries lists the latest markets created for a series.
+func (m *MarketModel) ForSeries(seriesID uuid.UUID) ([]*Market, error) {
+	stmt := `SELECT
+		id,
+		title,
+		description,
+		category,
+		resolver_type,
+		resolver_ref,
+		expires_at,
+		status,
+		created_by,
+		resolved_outcome_id,
+		resolved_at,
+		resolved_by,
+		group_id,
+		kind,
+		event_id,
+		event_group
+	FROM markets
+	WHERE series_id = $1
+	  AND hidden_at IS NULL
+	ORDER BY expires_at DESC
+	LIMIT 50`
+
+	return m.list(stmt, seriesID)
+}
+
+// ForEvent lists the markets of an event visible to viewerID, whether open
+// or not, in the order they were added. Markets still waiting on their
+// resolver are left out.
+func (m *MarketModel) ForEvent(eventID, viewerID uuid.UUID) ([]*Market, error) {
+	stmt := `SELECT
+		id,
+		title,
+		description,
+		category,
+		resolver_type,
+		resolver_ref,
+		expires_at,
+		status,
+		created_by,
+		resolved_outcome_id,
+		resolved_at,
+		resolved_by,
+		group_id,
+		kind,
+		event_id,
+		event_group
+	FROM markets
+	WHERE event_id = $1
+	  AND status <> 'pending'
+	  AND hidden_at IS NULL
+	  AND (group_id IS NULL OR group_id IN (SELECT group_id FROM group_members WHERE user_id = $2))
+	ORDER BY created_at
+	LIMIT 200`
+
+	return m.list(stmt, eventID, viewerID)
+}
+
+func (m *MarketModel) list(stmt string, args ...any) ([]*Market, error) {
+	rows, err := m.DB.Query(stmt, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -132,6 +346,10 @@ func (m *MarketModel) Latest() ([]*Market, error) {
 			&market.ResolvedOutcomeID,
 			&market.ResolvedAt,
 			&market.ResolvedBy,
+			&market.GroupID,
+			&market.Kind,
+			&market.EventID,
+			&market.EventGroup,
 		)
 		if err != nil {
 			return nil, err
@@ -148,20 +366,61 @@ func (m *MarketModel) Latest() ([]*Market, error) {
 
 func (m *MarketModel) Get(id uuid.UUID) (Market, error) {
 	stmt := `SELECT
-		id,
-		title,
-		description,
-		category,
-		resolver_type,
-		resolver_ref,
-		expires_at,
-		status,
-		created_by,
-		resolved_outcome_id,
-		resolved_at,
-		resolved_by
-	FROM markets
-	WHERE id = $1`
+		m.id,
+		m.title,
+		m.description,
+		m.category,
+		m.resolver_type,
+		m.resolver_ref,
+		m.expires_at,
+		m.status,
+		m.created_by,
+		m.resolved_outcome_id,
+		m.resolved_at,
+		m.resolved_by,
+		m.group_id,
+		m.hidden_at,
+		m.dispute_window_hours,
+		m.proposed_outcome_id,
+		m.proposed_by,
+		m.proposed_at,
+		m.finalizes_at,
+		m.platform_fee_bps,
+		m.creator_fee_bps,
+		m.kind,
+		m.scalar_lower,
+		m.scalar_upper,
+		m.scalar_log,
+		m.resolved_value,
+		m.bucket_lower,
+		m.bucket_upper,
+		m.bucket_size,
+		m.condition_market_id,
+		m.condition_outcome_id,
+		m.series_id,
+		m.event_id,
+		m.event_group,
+		m.integrity_rule,
+		(SELECT COALESCE(SUM(amount), 0) FROM liquidity_positions WHERE market_id = m.id),
+		c.username,
+		r.username,
+		rb.username,
+		pb.username,
+		g.name,
+		cm.title,
+		cm.status,
+		co.label,
+		ev.title
+	FROM markets m
+	JOIN users c ON c.id = m.created_by
+	LEFT JOIN users r ON r.id = m.resolver_ref
+	LEFT JOIN users rb ON rb.id = m.resolved_by
+	LEFT JOIN users pb ON pb.id = m.proposed_by
+	LEFT JOIN groups g ON g.id = m.group_id
+	LEFT JOIN markets cm ON cm.id = m.condition_market_id
+	LEFT JOIN outcomes co ON co.id = m.condition_outcome_id
+	LEFT JOIN events ev ON ev.id = m.event_id
+	WHERE m.id = $1`
 
 	var market Market
 	err := m.DB.QueryRow(stmt, id).Scan(
@@ -177,27 +436,138 @@ func (m *MarketModel) Get(id uuid.UUID) (Market, error) {
 		&market.ResolvedOutcomeID,
 		&market.ResolvedAt,
 		&market.ResolvedBy,
+		&market.GroupID,
+		&market.HiddenAt,
+		&market.DisputeWindowHours,
+		&market.ProposedOutcomeID,
+		&market.ProposedBy,
+		&market.ProposedAt,
+		&market.FinalizesAt,
+		&market.PlatformFeeBps,
+		&market.CreatorFeeBps,
+		&market.Kind,
+		&market.ScalarLower,
+		&market.ScalarUpper,
+		&market.ScalarLog,
+		&market.ResolvedValue,
+		&market.BucketLower,
+		&market.BucketUpper,
+		&market.BucketSize,
+		&market.ConditionMarketID,
+		&market.ConditionOutcomeID,
+		&market.SeriesID,
+		&market.EventID,
+		&market.EventGroup,
+		&market.IntegrityRule,
+		&market.Liquidity,
+		&market.CreatorUsername,
+		&market.ResolverUsername,
+		&market.ResolvedByUsername,
+		&market.ProposedByUsername,
+		&market.GroupName,
+		&market.ConditionMarketTitle,
+		&market.ConditionMarketStatus,
+		&market.ConditionOutcomeLabel,
+		&market.EventTitle,
 	)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Market{}, ErrNoRecord
+		}
 		return Market{}, err
 	}
 
 	return market, nil
 }
 
-func (m *MarketModel) PendingResolution(userID uuid.UUID) ([]Market, error) {
+func (m *MarketModel) CreatedByUser(userID uuid.UUID) ([]Market, error) {
+	stmt := `SELECT
+		id,
+		title,
+		category,
+		status,
+		expires_at,
+		resolved_at
+	FROM markets
+	WHERE created_by = $1
+	  AND group_id IS NULL
+	  AND hidden_at IS NULL
+	ORDER BY created_at DESC
+	LIMIT 20`
+
+	return m.profileMarkets(stmt, userID)
+}
+
+func (m *MarketModel) ResolvedByUser(userID uuid.UUID) ([]Market, error) {
+	stmt := `SELECT
+		id,
+		title,
+		category,
+		status,
+		expires_at,
+		resolved_at
+	FROM markets
+	WHERE resolved_by = $1
+	  AND group_id IS NULL
+	  AND hidden_at IS NULL
+	ORDER BY resolved_at DESC
+	LIMIT 20`
+
+	return m.profileMarkets(stmt, userID)
+}
+
+func (m *MarketModel) profileMarkets(stmt string, userID uuid.UUID) ([]Market, error) {
+	rows, err := m.DB.Query(stmt, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var markets []Market
+
+	for rows.Next() {
+		var market Market
+		err = rows.Scan(
+			&market.ID,
+			&market.Title,
+			&market.Category,
+			&market.Status,
+			&market.ExpiresAt,
+			&market.ResolvedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		markets = append(markets, market)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return markets, nil
+}
+
+// PendingResolution lists expired markets waiting on userID. Admins also
+// see every market with the admin resolver type, and oracle markets whose
+// oracle gave up.
+func (m *MarketModel) PendingResolution(userID uuid.UUID, isAdmin bool) ([]Market, error) {
 	stmt := `SELECT
 		id,
 		title,
 		category,
 		expires_at
 	FROM markets
-	WHERE resolver_ref = $1
+	WHERE (resolver_ref = $1
+	       OR (resolver_type = 'admin' AND $2)
+	       OR (resolver_type = 'oracle' AND $2 AND id IN (SELECT market_id FROM market_oracles WHERE failed_at IS NOT NULL)))
 	  AND expires_at < NOW()
 	  AND resolved_outcome_id IS NULL
+	  AND status = 'open'
+	  AND (condition_market_id IS NULL OR condition_market_id IN (SELECT id FROM markets WHERE status = 'resolved'))
 	ORDER BY expires_at`
 
-	rows, err := m.DB.Query(stmt, userID)
+	rows, err := m.DB.Query(stmt, userID, isAdmin)
 	if err != nil {
 		return nil, err
 	}
@@ -233,7 +603,8 @@ func (m *MarketModel) ResolveMarket(tx *sql.Tx, marketID uuid.UUID, userID uuid.
 		    resolved_at = NOW(),
 		    resolved_by = $2
 		WHERE id = $3
-		  AND resolved_outcome_id IS NULL`
+		  AND resolved_outcome_id IS NULL
+		  AND status IN ('open', 'proposed', 'disputed')`
 
 	res, err := tx.Exec(stmt, outcomeID, userID, marketID)
 	if err != nil {
@@ -252,14 +623,291 @@ func (m *MarketModel) ResolveMarket(tx *sql.Tx, marketID uuid.UUID, userID uuid.
 	return nil
 }
 
+// Propose records the resolver's answer and starts the dispute window. The
+// market is settled later by ResolveMarket.
+func (m *MarketModel) Propose(tx *sql.Tx, marketID uuid.UUID, userID uuid.UUID, outcomeID uuid.UUID, finalizesAt time.Time) error {
+	stmt := `UPDATE markets
+		SET status = 'proposed',
+		    proposed_outcome_id = $1,
+		    proposed_by = $2,
+		    proposed_at = NOW(),
+		    finalizes_at = $3
+		WHERE id = $4
+		  AND status = 'open'`
+
+	res, err := tx.Exec(stmt, outcomeID, userID, finalizesAt, marketID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrMarketAlreadyResolved
+	}
+
+	return nil
+}
+
+func (m *MarketModel) MarkDisputed(tx *sql.Tx, marketID uuid.UUID) error {
+	stmt := `UPDATE markets SET status = 'disputed' WHERE id = $1 AND status = 'proposed'`
+	_, err := tx.Exec(stmt, marketID)
+	return err
+}
+
+// DueProposals returns the proposed markets whose dispute window has closed.
+func (m *MarketModel) DueProposals() ([]uuid.UUID, error) {
+	stmt := `SELECT id FROM markets WHERE status = 'proposed' AND finalizes_at <= NOW() ORDER BY finalizes_at`
+
+	rows, err := m.DB.Query(stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+
+	for rows.Next() {
+		var id uuid.UUID
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (m *MarketModel) Disputed() ([]uuid.UUID, error) {
+	stmt := `SELECT id FROM markets WHERE status = 'disputed' ORDER BY proposed_at`
+
+	rows, err := m.DB.Query(stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+
+	for rows.Next() {
+		var id uuid.UUID
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Open opens a pending market for betting.
+func (m *MarketModel) Open(tx *sql.Tx, marketID uuid.UUID) error {
+	stmt := `UPDATE markets SET status = 'open' WHERE id = $1 AND status = 'pending'`
+
+	res, err := tx.Exec(stmt, marketID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrMarketAlreadyResolved
+	}
+
+	return nil
+}
+
+// ClearResolution reopens a resolved market inside a correction so it can
+// be settled again.
+func (m *MarketModel) ClearResolution(tx *sql.Tx, marketID uuid.UUID) error {
+	stmt := `UPDATE markets
+		SET status = 'open',
+		    resolved_outcome_id = NULL,
+		    resolved_at = NULL,
+		    resolved_by = NULL,
+		    resolved_value = NULL
+		WHERE id = $1
+		  AND status = 'resolved'`
+
+	_, err := tx.Exec(stmt, marketID)
+	return err
+}
+
+// SetResolvedValue records the answer to a scalar market. Pass nil to clear
+// it when the market is settled some other way.
+func (m *MarketModel) SetResolvedValue(tx *sql.Tx, marketID uuid.UUID, value *float64) error {
+	_, err := tx.Exec(`UPDATE markets SET resolved_value = $1 WHERE id = $2`, value, marketID)
+	return err
+}
+
+func (m *MarketModel) Hide(tx *sql.Tx, id uuid.UUID) error {
+	stmt := `UPDATE markets SET hidden_at = NOW() WHERE id = $1 AND hidden_at IS NULL`
+	_, err := tx.Exec(stmt, id)
+	return err
+}
+
+// Void closes the market without a winner. It records who voided it in the
+// resolution columns so profiles and the audit trail agree. Pending markets
+// can be voided too.
+func (m *MarketModel) Void(tx *sql.Tx, id uuid.UUID, userID uuid.UUID) error {
+	stmt := `UPDATE markets
+		SET status = 'void',
+		    resolved_at = NOW(),
+		    resolved_by = $1
+		WHERE id = $2
+		  AND status IN ('open', 'pending')`
+
+	res, err := tx.Exec(stmt, userID, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrMarketAlreadyResolved
+	}
+
+	return nil
+}
+
+// ConditionalOn lists the markets that depend on an outcome of marketID.
+// Only ID, Title, Status, HiddenAt, ConditionOutcomeID and
+// ConditionOutcomeLabel are set.
+func (m *MarketModel) ConditionalOn(marketID uuid.UUID) ([]Market, error) {
+	stmt := `SELECT m.id, m.title, m.status, m.hidden_at, m.condition_outcome_id, o.label
+	FROM markets m
+	JOIN outcomes o ON o.id = m.condition_outcome_id
+	WHERE m.condition_market_id = $1
+	ORDER BY m.created_at`
+
+	rows, err := m.DB.Query(stmt, marketID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var markets []Market
+
+	for rows.Next() {
+		var market Market
+		err = rows.Scan(
+			&market.ID,
+			&market.Title,
+			&market.Status,
+			&market.HiddenAt,
+			&market.ConditionOutcomeID,
+			&market.ConditionOutcomeLabel,
+		)
+		if err != nil {
+			return nil, err
+		}
+		markets = append(markets, market)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return markets, nil
+}
+
+// OpenConditionalOn locks the open and pending markets that depend on
+// marketID. Only ID and ConditionOutcomeID are set.
+func (m *MarketModel) OpenConditionalOn(tx *sql.Tx, marketID uuid.UUID) ([]Market, error) {
+	stmt := `SELECT id, condition_outcome_id
+	FROM markets
+	WHERE condition_market_id = $1
+	  AND status IN ('open', 'pending')
+	FOR UPDATE`
+
+	rows, err := tx.Query(stmt, marketID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var markets []Market
+
+	for rows.Next() {
+		var market Market
+		err = rows.Scan(&market.ID, &market.ConditionOutcomeID)
+		if err != nil {
+			return nil, err
+		}
+		markets = append(markets, market)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return markets, nil
+}
+
+// LockForShare blocks edits to the market's terms until tx ends, see
+// MarketService.Edit.
+func (m *MarketModel) LockForShare(tx *sql.Tx, id uuid.UUID) error {
+	var locked uuid.UUID
+	stmt := `SELECT id FROM markets WHERE id = $1 FOR SHARE`
+	return tx.QueryRow(stmt, id).Scan(&locked)
+}
+
+func (m *MarketModel) Update(tx *sql.Tx, market Market) error {
+	stmt := `UPDATE markets
+		SET title = $1, description = $2, category = $3, expires_at = $4
+		WHERE id = $5`
+
+	_, err := tx.Exec(stmt, market.Title, market.Description, market.Category, market.ExpiresAt, market.ID)
+	return err
+}
+
 func (m *MarketModel) SelectForUpdate(tx *sql.Tx, id uuid.UUID) (Market, error) {
 	stmt := `SELECT
 		id,
+		title,
+		description,
+		category,
 		resolver_type,
 		resolver_ref,
 		expires_at,
 		status,
-		resolved_outcome_id
+		created_by,
+		resolved_outcome_id,
+		resolved_value,
+		dispute_window_hours,
+		proposed_outcome_id,
+		proposed_by,
+		finalizes_at,
+		platform_fee_bps,
+		creator_fee_bps,
+		kind,
+		scalar_lower,
+		scalar_upper,
+		scalar_log,
+		condition_market_id,
+		condition_outcome_id,
+		integrity_rule
 		FROM markets
 		WHERE id = $1
 		FOR UPDATE`
@@ -267,15 +915,47 @@ func (m *MarketModel) SelectForUpdate(tx *sql.Tx, id uuid.UUID) (Market, error)
 	var market Market
 	err := tx.QueryRow(stmt, id).Scan(
 		&market.ID,
+		&market.Title,
+		&market.Description,
+		&market.Category,
 		&market.ResolverType,
 		&market.ResolverRef,
 		&market.ExpiresAt,
 		&market.Status,
+		&market.CreatedBy,
 		&market.ResolvedOutcomeID,
+		&market.ResolvedValue,
+		&market.DisputeWindowHours,
+		&market.ProposedOutcomeID,
+		&market.ProposedBy,
+		&market.FinalizesAt,
+		&market.PlatformFeeBps,
+		&market.CreatorFeeBps,
+		&market.Kind,
+		&market.ScalarLower,
+		&market.ScalarUpper,
+		&market.ScalarLog,
+		&market.ConditionMarketID,
+		&market.ConditionOutcomeID,
+		&market.IntegrityRule,
 	)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Market{}, ErrNoRecord
+		}
 		return Market{}, err
 	}
 
 	return market, nil
 }
+
+// CountResolvedBy counts the markets userID resolved by hand. Oracle
+// markets are left out, as they are resolved in their creator's name.
+func (m *MarketModel) CountResolvedBy(userID uuid.UUID) (int, error) {
+	var count int
+	stmt := `SELECT COUNT(*) FROM markets WHERE resolved_by = $1 AND resolver_type <> 'oracle'`
+
+	err := m.DB.QueryRow(stmt, userID).Scan(&count)
+
+	return count, err
+}
@@ -0,0 +1,236 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ReportTarget string
+
+const (
+	ReportMarket  ReportTarget = "market"
+	ReportComment ReportTarget = "comment"
+)
+
+type ReportReason string
+
+const (
+	ReasonSpam       ReportReason = "spam"
+	ReasonOffensive  ReportReason = "offensive"
+	ReasonMisleading ReportReason = "misleading"
+	ReasonDuplicate  ReportReason = "duplicate"
+	ReasonOther      ReportReason = "other"
+)
+
+func AllReportReasons() []ReportReason {
+	return []ReportReason{
+		ReasonSpam,
+		ReasonOffensive,
+		ReasonMisleading,
+		ReasonDuplicate,
+		ReasonOther,
+	}
+}
+
+type ReportStatus string
+
+const (
+	ReportOpen      ReportStatus = "open"
+	ReportDismissed ReportStatus = "dismissed"
+	ReportActioned  ReportStatus = "actioned"
+)
+
+type ModerationAction string
+
+const (
+	ActionDismiss ModerationAction = "dismiss"
+	ActionHide    ModerationAction = "hide"
+	ActionVoid    ModerationAction = "void"
+	ActionSuspend ModerationAction = "suspend"
+)
+
+func AllModerationActions() []ModerationAction {
+	return []ModerationAction{
+		ActionDismiss,
+		ActionHide,
+		ActionVoid,
+		ActionSuspend,
+	}
+}
+
+// ReportedItem groups the open reports filed against one market or comment.
+type ReportedItem struct {
+	TargetType     ReportTarget
+	TargetID       uuid.UUID
+	MarketID       uuid.UUID
+	MarketTitle    string
+	MarketStatus   string
+	CommentBody    *string
+	AuthorID       uuid.UUID
+	AuthorUsername string
+	Reports        int
+	Reasons        string
+	Details        string
+	LastReportedAt time.Time
+}
+
+type ModerationLogEntry struct {
+	ID                uuid.UUID
+	ModeratorID       uuid.UUID
+	ModeratorUsername string
+	Action            ModerationAction
+	TargetType        ReportTarget
+	TargetID          uuid.UUID
+	Note              string
+	CreatedAt         time.Time
+}
+
+type ReportModel struct {
+	DB *sql.DB
+}
+
+// Insert files a report. Reporting the same item twice is a no-op so
+// repeated clicks do not inflate the queue.
+func (m *ReportModel) Insert(targetType ReportTarget, targetID, marketID, reporterID uuid.UUID, reason ReportReason, details string) error {
+	stmt := `INSERT INTO reports (target_type, target_id, market_id, reporter_id, reason, details)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (target_type, target_id, reporter_id) DO NOTHING`
+
+	_, err := m.DB.Exec(stmt, targetType, targetID, marketID, reporterID, reason, details)
+	return err
+}
+
+func (m *ReportModel) Queue() ([]ReportedItem, error) {
+	stmt := `SELECT
+		r.target_type,
+		r.target_id,
+		r.market_id,
+		mk.title,
+		mk.status,
+		c.body,
+		u.id,
+		u.username,
+		COUNT(*),
+		string_agg(DISTINCT r.reason, ', '),
+		COALESCE(string_agg(NULLIF(r.details, ''), ' · '), ''),
+		MAX(r.created_at)
+	FROM reports r
+	JOIN markets mk ON mk.id = r.market_id
+	LEFT JOIN comments c ON r.target_type = 'comment' AND c.id = r.target_id
+	JOIN users u ON u.id = COALESCE(c.user_id, mk.created_by)
+	WHERE r.status = 'open'
+	GROUP BY r.target_type, r.target_id, r.market_id, mk.title, mk.status, c.body, u.id, u.username
+	ORDER BY COUNT(*) DESC, MAX(r.created_at)`
+
+	rows, err := m.DB.Query(stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []ReportedItem
+
+	for rows.Next() {
+		var i ReportedItem
+		err = rows.Scan(
+			&i.TargetType,
+			&i.TargetID,
+			&i.MarketID,
+			&i.MarketTitle,
+			&i.MarketStatus,
+			&i.CommentBody,
+			&i.AuthorID,
+			&i.AuthorUsername,
+			&i.Reports,
+			&i.Reasons,
+			&i.Details,
+			&i.LastReportedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Close resolves every open report against the target.
+func (m *ReportModel) Close(tx *sql.Tx, targetType ReportTarget, targetID uuid.UUID, status ReportStatus, moderatorID uuid.UUID) error {
+	stmt := `UPDATE reports
+		SET status = $1, resolved_at = NOW(), resolved_by = $2
+		WHERE target_type = $3 AND target_id = $4 AND status = 'open'`
+
+	_, err := tx.Exec(stmt, status, moderatorID, targetType, targetID)
+	return err
+}
+
+// ReportedAuthor returns who wrote the reported market or comment.
+func (m *ReportModel) ReportedAuthor(tx *sql.Tx, targetType ReportTarget, targetID uuid.UUID) (uuid.UUID, error) {
+	stmt := `SELECT created_by FROM markets WHERE id = $1`
+	if targetType == ReportComment {
+		stmt = `SELECT user_id FROM comments WHERE id = $1`
+	}
+
+	var id uuid.UUID
+	err := tx.QueryRow(stmt, targetID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.UUID{}, ErrNoRecord
+		}
+
+		return uuid.UUID{}, err
+	}
+
+	return id, nil
+}
+
+type ModerationLogModel struct {
+	DB *sql.DB
+}
+
+func (m *ModerationLogModel) Insert(tx *sql.Tx, moderatorID uuid.UUID, action ModerationAction, targetType ReportTarget, targetID uuid.UUID, note string) error {
+	stmt := `INSERT INTO moderation_actions (moderator_id, action, target_type, target_id, note)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := tx.Exec(stmt, moderatorID, action, targetType, targetID, note)
+	return err
+}
+
+func (m *ModerationLogModel) Recent(limit int) ([]ModerationLogEntry, error) {
+	stmt := `SELECT a.id, a.moderator_id, u.username, a.action, a.target_type, a.target_id, a.note, a.created_at
+		FROM moderation_actions a
+		JOIN users u ON u.id = a.moderator_id
+		ORDER BY a.created_at DESC
+		LIMIT $1`
+
+	rows, err := m.DB.Query(stmt, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []ModerationLogEntry
+
+	for rows.Next() {
+		var e ModerationLogEntry
+		err = rows.Scan(&e.ID, &e.ModeratorID, &e.ModeratorUsername, &e.Action, &e.TargetType, &e.TargetID, &e.Note, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type NotificationKind string
+
+const (
+	NotificationResolverRequested NotificationKind = "resolver_requested"
+	NotificationResolverAccepted  NotificationKind = "resolver_accepted"
+	NotificationResolverDeclined  NotificationKind = "resolver_declined"
+)
+
+type Notification struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Kind      NotificationKind
+	Message   string
+	Link      string
+	ReadAt    *time.Time
+	CreatedAt time.Time
+}
+
+type NotificationModel struct {
+	DB *sql.DB
+}
+
+func (m *NotificationModel) Insert(userID uuid.UUID, kind NotificationKind, message, link string) error {
+	stmt := `INSERT INTO notifications (user_id, kind, message, link) VALUES ($1, $2, $3, $4)`
+	_, err := m.DB.Exec(stmt, userID, kind, message, link)
+	return err
+}
+
+// ForUser returns the user's latest notifications, newest first.
+func (m *NotificationModel) ForUser(userID uuid.UUID) ([]Notification, error) {
+	stmt := `SELECT id, user_id, kind, message, link, read_at, created_at
+	FROM notifications
+	WHERE user_id = $1
+	ORDER BY created_at DESC
+	LIMIT 50`
+
+	rows, err := m.DB.Query(stmt, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []Notification
+
+	for rows.Next() {
+		var n Notification
+		err = rows.Scan(&n.ID, &n.UserID, &n.Kind, &n.Message, &n.Link, &n.ReadAt, &n.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (m *NotificationModel) Unread(userID uuid.UUID) (int, error) {
+	var count int
+	stmt := `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`
+	err := m.DB.QueryRow(stmt, userID).Scan(&count)
+	return count, err
+}
+
+func (m *NotificationModel) MarkAllRead(userID uuid.UUID) error {
+	stmt := `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`
+	_, err := m.DB.Exec(stmt, userID)
+	return err
+}
@@ -0,0 +1,194 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Oracle is the data source an oracle market is resolved from and how its
+// latest attempt went. Operator is empty for markets answered with the
+// value itself.
+type Oracle struct {
+	MarketID  uuid.UUID
+	Source    string
+	Target    string
+	Path      string
+	Operator  string
+	Threshold *float64
+
+	Attempts  int
+	LastValue *float64
+	LastError *string
+	CheckedAt *time.Time
+	FailedAt  *time.Time
+
+	MarketTitle     string
+	MarketExpiresAt time.Time
+}
+
+type OracleModel struct {
+	DB *sql.DB
+}
+
+func (m *OracleModel) Insert(tx *sql.Tx, o Oracle) error {
+	stmt := `INSERT INTO market_oracles (market_id, source, target, path, operator, threshold)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := tx.Exec(stmt, o.MarketID, o.Source, o.Target, o.Path, o.Operator, o.Threshold)
+	return err
+}
+
+const oracleColumns = `o.market_id, o.source, o.target, o.path, o.operator, o.threshold,
+	o.attempts, o.last_value, o.last_error, o.checked_at, o.failed_at,
+	m.title, m.expires_at`
+
+func scanOracle(row interface{ Scan(...any) error }) (Oracle, error) {
+	var o Oracle
+	err := row.Scan(
+		&o.MarketID,
+		&o.Source,
+		&o.Target,
+		&o.Path,
+		&o.Operator,
+		&o.Threshold,
+		&o.Attempts,
+		&o.LastValue,
+		&o.LastError,
+		&o.CheckedAt,
+		&o.FailedAt,
+		&o.MarketTitle,
+		&o.MarketExpiresAt,
+	)
+	return o, err
+}
+
+func (m *OracleModel) ForMarket(marketID uuid.UUID) (Oracle, error) {
+	stmt := `SELECT ` + oracleColumns + `
+	FROM market_oracles o
+	JOIN markets m ON m.id = o.market_id
+	WHERE o.market_id = $1`
+
+	o, err := scanOracle(m.DB.QueryRow(stmt, marketID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Oracle{}, ErrNoRecord
+		}
+		return Oracle{}, err
+	}
+
+	return o, nil
+}
+
+// Due lists the oracles of open, expired markets that have not given up,
+// leaving retryAfter between attempts. Conditional markets wait for the
+// market they depend on to resolve.
+func (m *OracleModel) Due(retryAfter time.Duration) ([]Oracle, error) {
+	stmt := `SELECT ` + oracleColumns + `
+	FROM market_oracles o
+	JOIN markets m ON m.id = o.market_id
+	WHERE m.status = 'open'
+	  AND m.expires_at <= NOW()
+	  AND o.failed_at IS NULL
+	  AND (o.checked_at IS NULL OR o.checked_at <= NOW() - make_interval(secs => $1))
+	  AND (m.condition_market_id IS NULL OR m.condition_market_id IN (SELECT id FROM markets WHERE status = 'resolved'))
+	ORDER BY m.expires_at`
+
+	return m.query(stmt, retryAfter.Seconds())
+}
+
+// Failed lists the open markets whose oracle gave up, for admins to
+// resolve by hand.
+func (m *OracleModel) Failed() ([]Oracle, error) {
+	stmt := `SELECT ` + oracleColumns + `
+	FROM market_oracles o
+	JOIN markets m ON m.id = o.market_id
+	WHERE m.status = 'open'
+	  AND o.failed_at IS NOT NULL
+	ORDER BY o.failed_at`
+
+	return m.query(stmt)
+}
+
+func (m *OracleModel) query(stmt string, args ...any) ([]Oracle, error) {
+	rows, err := m.DB.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var oracles []Oracle
+
+	for rows.Next() {
+		o, err := scanOracle(rows)
+		if err != nil {
+			return nil, err
+		}
+		oracles = append(oracles, o)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return oracles, nil
+}
+
+// RecordValue records a successful reading.
+func (m *OracleModel) RecordValue(marketID uuid.UUID, value float64) error {
+	stmt := `UPDATE market_oracles
+		SET attempts = attempts + 1, last_value = $2, last_error = NULL, checked_at = NOW()
+		WHERE market_id = $1`
+
+	_, err := m.DB.Exec(stmt, marketID, value)
+	return err
+}
+
+// RecordUnused records a reading that could not be used to resolve the
+// market, with the reason why. Unlike a failure it never makes the oracle
+// give up.
+func (m *OracleModel) RecordUnused(marketID uuid.UUID, value float64, reason string) error {
+	stmt := `UPDATE market_oracles
+		SET attempts = attempts + 1, last_value = $2, last_error = $3, checked_at = NOW()
+		WHERE market_id = $1`
+
+	_, err := m.DB.Exec(stmt, marketID, value, reason)
+	return err
+}
+
+// RecordFailure records a failed attempt, giving up once maxAttempts have
+// failed.
+func (m *OracleModel) RecordFailure(marketID uuid.UUID, reason string, maxAttempts int) error {
+	stmt := `UPDATE market_oracles
+		SET attempts = attempts + 1,
+		    last_error = $2,
+		    checked_at = NOW(),
+		    failed_at = CASE WHEN attempts + 1 >= $3 THEN NOW() END
+		WHERE market_id = $1`
+
+	_, err := m.DB.Exec(stmt, marketID, reason, maxAttempts)
+	return err
+}
+
+// Retry lets the oracle try again from scratch after it gave up.
+func (m *OracleModel) Retry(marketID uuid.UUID) error {
+	stmt := `UPDATE market_oracles SET attempts = 0, checked_at = NULL, failed_at = NULL WHERE market_id = $1 AND failed_at IS NOT NULL`
+
+	res, err := m.DB.Exec(stmt, marketID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrNoRecord
+	}
+
+	return nil
+}
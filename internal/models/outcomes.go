@@ -14,35 +14,82 @@ type Outcome struct {
 	Label      string
 	createdAt  sql.NullTime
 	PoolAmount int
+
+	// ResolvedShareBps is the part of the pool, in basis points, the
+	// outcome received when the market was resolved.
+	ResolvedShareBps *int
+
+	// Buckets cover LowerBound up to but excluding UpperBound. Either is nil
+	// for the open ended first and last bucket.
+	Position   int
+	LowerBound *float64
+	UpperBound *float64
+}
+
+// Bucket is a generated outcome of a bucket or date market.
+type Bucket struct {
+	Label string
+	Lower *float64
+	Upper *float64
 }
 
 type OutcomeModel struct {
 	DB *sql.DB
 }
 
-const yesLabel = "yes"
-const noLabel = "no"
+const YesLabel = "yes"
+const NoLabel = "no"
+
+// Scalar markets have a long outcome, paid more the higher the answer, and a
+// short one paid more the lower it is.
+const (
+	LongLabel  = "long"
+	ShortLabel = "short"
+)
 
 func (m *OutcomeModel) CreateWithYesNo(tx *sql.Tx, marketId uuid.UUID) error {
+	return m.createWithLabels(tx, marketId, YesLabel, NoLabel)
+}
+
+func (m *OutcomeModel) CreateWithLongShort(tx *sql.Tx, marketId uuid.UUID) error {
+	return m.createWithLabels(tx, marketId, LongLabel, ShortLabel)
+}
+
+// CreateBuckets adds the buckets as outcomes in the order given.
+func (m *OutcomeModel) CreateBuckets(tx *sql.Tx, marketId uuid.UUID, buckets []Bucket) error {
+	stmt := `INSERT INTO outcomes (market_id, label, position, lower_bound, upper_bound)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	for i, b := range buckets {
+		_, err := tx.Exec(stmt, marketId, b.Label, i, b.Lower, b.Upper)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *OutcomeModel) createWithLabels(tx *sql.Tx, marketId uuid.UUID, labels ...string) error {
 	stmt := `INSERT INTO outcomes (
             	market_id, label                
 			) VALUES ($1, $2)`
 
-	_, err := tx.Exec(stmt, marketId, yesLabel)
-	if err != nil {
-		return err
-	}
-
-	_, err = tx.Exec(stmt, marketId, noLabel)
-	if err != nil {
-		return err
+	for _, label := range labels {
+		_, err := tx.Exec(stmt, marketId, label)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
 func (m *OutcomeModel) ForMarkets(ids []uuid.UUID) (map[uuid.UUID][]Outcome, error) {
-	stmt := `SELECT id, market_id, label, pool_amount FROM outcomes WHERE market_id = ANY($1)`
+	stmt := `SELECT id, market_id, label, pool_amount, resolved_share_bps, position, lower_bound, upper_bound
+		FROM outcomes
+		WHERE market_id = ANY($1)
+		ORDER BY position, created_at`
 
 	rows, err := m.DB.Query(stmt, pq.Array(ids))
 	if err != nil {
@@ -55,7 +102,7 @@ func (m *OutcomeModel) ForMarkets(ids []uuid.UUID) (map[uuid.UUID][]Outcome, err
 
 	for rows.Next() {
 		var o Outcome
-		err = rows.Scan(&o.ID, &o.MarketID, &o.Label, &o.PoolAmount)
+		err = rows.Scan(&o.ID, &o.MarketID, &o.Label, &o.PoolAmount, &o.ResolvedShareBps, &o.Position, &o.LowerBound, &o.UpperBound)
 		if err != nil {
 			return nil, err
 		}
@@ -70,7 +117,10 @@ func (m *OutcomeModel) ForMarkets(ids []uuid.UUID) (map[uuid.UUID][]Outcome, err
 }
 
 func (m *OutcomeModel) ForMarket(id uuid.UUID) ([]Outcome, error) {
-	stmt := `SELECT id, market_id, label, pool_amount FROM outcomes WHERE market_id = $1`
+	stmt := `SELECT id, market_id, label, pool_amount, resolved_share_bps, position, lower_bound, upper_bound
+		FROM outcomes
+		WHERE market_id = $1
+		ORDER BY position, created_at`
 
 	rows, err := m.DB.Query(stmt, id)
 	if err != nil {
@@ -83,7 +133,7 @@ func (m *OutcomeModel) ForMarket(id uuid.UUID) ([]Outcome, error) {
 
 	for rows.Next() {
 		var o Outcome
-		err = rows.Scan(&o.ID, &o.MarketID, &o.Label, &o.PoolAmount)
+		err = rows.Scan(&o.ID, &o.MarketID, &o.Label, &o.PoolAmount, &o.ResolvedShareBps, &o.Position, &o.LowerBound, &o.UpperBound)
 		if err != nil {
 			return nil, err
 		}
@@ -97,6 +147,51 @@ func (m *OutcomeModel) ForMarket(id uuid.UUID) ([]Outcome, error) {
 	return outcomes, nil
 }
 
+// IDsForMarket lists the market's outcomes inside tx, so it also sees a
+// market that has not been committed yet.
+func (m *OutcomeModel) IDsForMarket(tx *sql.Tx, marketID uuid.UUID) ([]uuid.UUID, error) {
+	stmt := `SELECT id FROM outcomes WHERE market_id = $1 ORDER BY position, label`
+
+	rows, err := tx.Query(stmt, marketID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+
+	for rows.Next() {
+		var id uuid.UUID
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// Get returns the outcome's ID, market and label.
+func (m *OutcomeModel) Get(id uuid.UUID) (Outcome, error) {
+	stmt := `SELECT id, market_id, label FROM outcomes WHERE id = $1`
+
+	var o Outcome
+	err := m.DB.QueryRow(stmt, id).Scan(&o.ID, &o.MarketID, &o.Label)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Outcome{}, ErrNoRecord
+		}
+		return Outcome{}, err
+	}
+
+	return o, nil
+}
+
 func (m *OutcomeModel) SelectForUpdate(tx *sql.Tx, id uuid.UUID) (Outcome, error) {
 	stmt := `SELECT id, market_id, pool_amount FROM outcomes WHERE id = $1 FOR UPDATE`
 
@@ -129,3 +224,49 @@ func (m *OutcomeModel) ExistsForMarket(tx *sql.Tx, outcomeID uuid.UUID, marketID
 
 	return true, nil
 }
+
+// SetResolvedShares replaces the resolution shares of the market's outcomes.
+// Outcomes missing from shares are cleared.
+func (m *OutcomeModel) SetResolvedShares(tx *sql.Tx, marketID uuid.UUID, shares map[uuid.UUID]int) error {
+	_, err := tx.Exec(`UPDATE outcomes SET resolved_share_bps = NULL WHERE market_id = $1`, marketID)
+	if err != nil {
+		return err
+	}
+
+	for id, bps := range shares {
+		_, err = tx.Exec(`UPDATE outcomes SET resolved_share_bps = $1 WHERE id = $2 AND market_id = $3`, bps, id, marketID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *OutcomeModel) ResolvedShares(tx *sql.Tx, marketID uuid.UUID) (map[uuid.UUID]int, error) {
+	stmt := `SELECT id, resolved_share_bps FROM outcomes WHERE market_id = $1 AND resolved_share_bps IS NOT NULL`
+
+	rows, err := tx.Query(stmt, marketID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shares := make(map[uuid.UUID]int)
+
+	for rows.Next() {
+		var id uuid.UUID
+		var bps int
+		err = rows.Scan(&id, &bps)
+		if err != nil {
+			return nil, err
+		}
+		shares[id] = bps
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return shares, nil
+}
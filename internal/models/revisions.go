@@ -0,0 +1,92 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RevisionField string
+
+const (
+	RevisionTitle         RevisionField = "title"
+	RevisionDescription   RevisionField = "description"
+	RevisionCategory      RevisionField = "category"
+	RevisionExpiresAt     RevisionField = "expires_at"
+	RevisionClarification RevisionField = "clarification"
+)
+
+func (f RevisionField) Label() string {
+	if f == RevisionExpiresAt {
+		return "expiry"
+	}
+	return string(f)
+}
+
+// MarketRevision is one changed field. Fields changed in the same save share
+// a revision number.
+type MarketRevision struct {
+	MarketID       uuid.UUID
+	Revision       int
+	EditedBy       uuid.UUID
+	EditorUsername string
+	Field          RevisionField
+	OldValue       *string
+	NewValue       string
+	CreatedAt      time.Time
+}
+
+type RevisionModel struct {
+	DB *sql.DB
+}
+
+// NextRevision must be called with the market row locked so two saves never
+// get the same number.
+func (m *RevisionModel) NextRevision(tx *sql.Tx, marketID uuid.UUID) (int, error) {
+	var next int
+	stmt := `SELECT COALESCE(MAX(revision), 0) + 1 FROM market_revisions WHERE market_id = $1`
+
+	err := tx.QueryRow(stmt, marketID).Scan(&next)
+
+	return next, err
+}
+
+func (m *RevisionModel) Insert(tx *sql.Tx, r MarketRevision) error {
+	stmt := `INSERT INTO market_revisions (market_id, revision, edited_by, field, old_value, new_value)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := tx.Exec(stmt, r.MarketID, r.Revision, r.EditedBy, r.Field, r.OldValue, r.NewValue)
+	return err
+}
+
+func (m *RevisionModel) ForMarket(marketID uuid.UUID) ([]MarketRevision, error) {
+	stmt := `SELECT r.market_id, r.revision, r.edited_by, u.username, r.field, r.old_value, r.new_value, r.created_at
+		FROM market_revisions r
+		JOIN users u ON u.id = r.edited_by
+		WHERE r.market_id = $1
+		ORDER BY r.revision DESC, r.created_at`
+
+	rows, err := m.DB.Query(stmt, marketID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revisions []MarketRevision
+
+	for rows.Next() {
+		var r MarketRevision
+		err = rows.Scan(&r.MarketID, &r.Revision, &r.EditedBy, &r.EditorUsername, &r.Field, &r.OldValue, &r.NewValue, &r.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, r)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
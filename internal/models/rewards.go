@@ -0,0 +1,47 @@
+package models
+
+import (
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+// RewardSchedule configures the daily reward. Every consecutive day adds
+// StreakStepBps basis points of BaseAmount on top of the base, up to
+// MaxAmount. GraceDays is how many calendar days can be missed without the
+// streak resetting.
+type RewardSchedule struct {
+	BaseAmount    int
+	StreakStepBps int
+	MaxAmount     int
+	GraceDays     int
+}
+
+type RewardScheduleModel struct {
+	DB *sql.DB
+}
+
+func (m *RewardScheduleModel) Get() (RewardSchedule, error) {
+	var rs RewardSchedule
+	stmt := `SELECT base_amount, streak_step_bps, max_amount, grace_days FROM reward_schedule`
+
+	err := m.DB.QueryRow(stmt).Scan(&rs.BaseAmount, &rs.StreakStepBps, &rs.MaxAmount, &rs.GraceDays)
+	if err != nil {
+		return RewardSchedule{}, err
+	}
+
+	return rs, nil
+}
+
+func (m *RewardScheduleModel) Update(rs RewardSchedule, adminID uuid.UUID) error {
+	stmt := `UPDATE reward_schedule
+		SET base_amount = $1,
+		    streak_step_bps = $2,
+		    max_amount = $3,
+		    grace_days = $4,
+		    updated_by = $5,
+		    updated_at = NOW()`
+
+	_, err := m.DB.Exec(stmt, rs.BaseAmount, rs.StreakStepBps, rs.MaxAmount, rs.GraceDays, adminID)
+	return err
+}
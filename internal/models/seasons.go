@@ -0,0 +1,293 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SeasonStatus string
+
+const (
+	SeasonScheduled SeasonStatus = "scheduled"
+	SeasonActive    SeasonStatus = "active"
+	SeasonArchived  SeasonStatus = "archived"
+)
+
+type Season struct {
+	ID              uuid.UUID
+	Name            string
+	StartsAt        time.Time
+	EndsAt          time.Time
+	StartingBalance int
+	Status          SeasonStatus
+	ArchivedAt      *time.Time
+	CreatedBy       uuid.UUID
+}
+
+type SeasonStanding struct {
+	SeasonID     uuid.UUID
+	SeasonName   string
+	UserID       uuid.UUID
+	Username     string
+	Rank         int
+	FinalBalance int
+	OpenStakes   int
+	NetProfit    int
+	Markets      int
+}
+
+type SeasonModel struct {
+	DB *sql.DB
+}
+
+const seasonColumns = `id, name, starts_at, ends_at, starting_balance, status, archived_at, created_by`
+
+func scanSeason(row interface{ Scan(...any) error }, s *Season) error {
+	return row.Scan(
+		&s.ID,
+		&s.Name,
+		&s.StartsAt,
+		&s.EndsAt,
+		&s.StartingBalance,
+		&s.Status,
+		&s.ArchivedAt,
+		&s.CreatedBy,
+	)
+}
+
+func (m *SeasonModel) Insert(name string, startsAt, endsAt time.Time, startingBalance int, createdBy uuid.UUID) error {
+	stmt := `INSERT INTO seasons (name, starts_at, ends_at, starting_balance, status, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := m.DB.Exec(stmt, name, startsAt, endsAt, startingBalance, SeasonScheduled, createdBy)
+	return err
+}
+
+func (m *SeasonModel) Overlaps(startsAt, endsAt time.Time) (bool, error) {
+	var overlaps bool
+	stmt := `SELECT EXISTS(SELECT true FROM seasons WHERE starts_at < $2 AND ends_at > $1)`
+
+	err := m.DB.QueryRow(stmt, startsAt, endsAt).Scan(&overlaps)
+
+	return overlaps, err
+}
+
+func (m *SeasonModel) All() ([]Season, error) {
+	return m.query(`SELECT ` + seasonColumns + ` FROM seasons ORDER BY starts_at DESC`)
+}
+
+func (m *SeasonModel) Archived() ([]Season, error) {
+	return m.query(`SELECT `+seasonColumns+` FROM seasons WHERE status = $1 ORDER BY starts_at DESC`, SeasonArchived)
+}
+
+func (m *SeasonModel) query(stmt string, args ...any) ([]Season, error) {
+	rows, err := m.DB.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seasons []Season
+
+	for rows.Next() {
+		var s Season
+		err = scanSeason(rows, &s)
+		if err != nil {
+			return nil, err
+		}
+		seasons = append(seasons, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return seasons, nil
+}
+
+func (m *SeasonModel) Get(id uuid.UUID) (Season, error) {
+	var s Season
+	err := scanSeason(m.DB.QueryRow(`SELECT `+seasonColumns+` FROM seasons WHERE id = $1`, id), &s)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Season{}, ErrNoRecord
+		}
+
+		return Season{}, err
+	}
+
+	return s, nil
+}
+
+// ActiveID returns the season bets placed right now belong to, or nil
+// outside of any season.
+func (m *SeasonModel) ActiveID(tx *sql.Tx) (*uuid.UUID, error) {
+	var id uuid.UUID
+	stmt := `SELECT id FROM seasons WHERE status = $1 LIMIT 1`
+
+	err := tx.QueryRow(stmt, SeasonActive).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &id, nil
+}
+
+// Due locks the next season whose status should change at now: a scheduled
+// season that has started or an active season that has ended. Seasons are
+// processed in start order so back to back seasons close before the next
+// one opens.
+func (m *SeasonModel) Due(tx *sql.Tx, now time.Time) (Season, error) {
+	stmt := `SELECT ` + seasonColumns + `
+		FROM seasons
+		WHERE (status = $1 AND starts_at <= $3)
+		   OR (status = $2 AND ends_at <= $3)
+		ORDER BY starts_at, status = $1
+		LIMIT 1
+		FOR UPDATE`
+
+	var s Season
+	err := scanSeason(tx.QueryRow(stmt, SeasonScheduled, SeasonActive, now), &s)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Season{}, ErrNoRecord
+		}
+
+		return Season{}, err
+	}
+
+	return s, nil
+}
+
+func (m *SeasonModel) SetStatus(tx *sql.Tx, id uuid.UUID, status SeasonStatus) error {
+	stmt := `UPDATE seasons
+		SET status = $1,
+		    archived_at = CASE WHEN $1 = 'archived' THEN NOW() ELSE archived_at END
+		WHERE id = $2`
+
+	_, err := tx.Exec(stmt, string(status), id)
+	return err
+}
+
+// ArchiveStandings snapshots every user's position at the end of the
+// season. The season's stakes in markets that are still unsettled are valued
+// at cost. Stakes placed outside any season are left out: they are paid out
+// into whichever season is running when their market settles, and counting
+// them here as well would count them twice.
+func (m *SeasonModel) ArchiveStandings(tx *sql.Tx, seasonID uuid.UUID) error {
+	stmt := `INSERT INTO season_standings
+		(season_id, user_id, rank, final_balance, open_stakes, net_profit, markets)
+	SELECT
+		$1,
+		u.id,
+		RANK() OVER (ORDER BY u.balance + COALESCE(o.stakes, 0) DESC),
+		u.balance + COALESCE(o.stakes, 0),
+		COALESCE(o.stakes, 0),
+		COALESCE(p.net_profit, 0),
+		COALESCE(p.markets, 0)
+	FROM users u
+	LEFT JOIN (
+		SELECT user_id, SUM(amount) AS stakes
+		FROM (
+			SELECT user_id, amount, payout_amount, season_id FROM bets
+			UNION ALL
+			SELECT user_id, amount, payout_amount, season_id FROM liquidity_positions WHERE user_id IS NOT NULL
+		) stakes
+		WHERE payout_amount IS NULL
+		  AND season_id = $1
+		GROUP BY user_id
+	) o ON o.user_id = u.id
+	LEFT JOIN (
+		SELECT user_id, SUM(payout_amount - amount) AS net_profit, COUNT(*) AS markets
+		FROM bets
+		WHERE payout_amount IS NOT NULL
+		  AND season_id = $1
+		GROUP BY user_id
+	) p ON p.user_id = u.id`
+
+	_, err := tx.Exec(stmt, seasonID)
+	return err
+}
+
+func (m *SeasonModel) Standings(seasonID uuid.UUID, limit int) ([]SeasonStanding, error) {
+	stmt := `SELECT
+		st.season_id,
+		s.name,
+		st.user_id,
+		u.username,
+		st.rank,
+		st.final_balance,
+		st.open_stakes,
+		st.net_profit,
+		st.markets
+	FROM season_standings st
+	JOIN seasons s ON s.id = st.season_id
+	JOIN users u ON u.id = st.user_id
+	WHERE st.season_id = $1
+	ORDER BY st.rank, u.username
+	LIMIT $2`
+
+	return m.standings(stmt, seasonID, limit)
+}
+
+func (m *SeasonModel) StandingsForUser(userID uuid.UUID) ([]SeasonStanding, error) {
+	stmt := `SELECT
+		st.season_id,
+		s.name,
+		st.user_id,
+		u.username,
+		st.rank,
+		st.final_balance,
+		st.open_stakes,
+		st.net_profit,
+		st.markets
+	FROM season_standings st
+	JOIN seasons s ON s.id = st.season_id
+	JOIN users u ON u.id = st.user_id
+	WHERE st.user_id = $1
+	ORDER BY s.starts_at DESC`
+
+	return m.standings(stmt, userID)
+}
+
+func (m *SeasonModel) standings(stmt string, args ...any) ([]SeasonStanding, error) {
+	rows, err := m.DB.Query(stmt, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var standings []SeasonStanding
+
+	for rows.Next() {
+		var st SeasonStanding
+		err = rows.Scan(
+			&st.SeasonID,
+			&st.SeasonName,
+			&st.UserID,
+			&st.Username,
+			&st.Rank,
+			&st.FinalBalance,
+			&st.OpenStakes,
+			&st.NetProfit,
+			&st.Markets,
+		)
+		if err != nil {
+			return nil, err
+		}
+		standings = append(standings, st)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return standings, nil
+}
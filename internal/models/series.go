@@ -0,0 +1,249 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Series is a template for a market asked over and over. The scheduler
+// keeps one market of the series open at a time, expiring at each
+// occurrence of Rule in turn.
+type Series struct {
+	ID                 uuid.UUID
+	Title              string
+	Description        string
+	Category           Category
+	ResolverType       ResolverType
+	GroupID            *uuid.UUID
+	DisputeWindowHours int
+	Liquidity          int
+	Rule               string
+	NextExpiresAt      time.Time
+	Instances          int
+	CreatedBy          uuid.UUID
+	StoppedAt          *time.Time
+	StopReason         *string
+	CreatedAt          time.Time
+
+	CreatorUsername string
+	GroupName       *string
+}
+
+// SeriesStats sums up every visible market of a series.
+type SeriesStats struct {
+	Markets  int
+	Open     int
+	Resolved int
+	Voided   int
+	Volume   int
+	Answers  []SeriesAnswer
+}
+
+// SeriesAnswer counts how often a series resolved to an outcome label, and
+// what percentage of its resolved markets that is.
+type SeriesAnswer struct {
+	Label   string
+	Count   int
+	Percent int
+}
+
+type SeriesModel struct {
+	DB *sql.DB
+}
+
+func (m *SeriesModel) Insert(tx *sql.Tx, s Series) (uuid.UUID, error) {
+	stmt := `INSERT INTO market_series
+		(title, description, category, resolver_type, group_id, dispute_window_hours, liquidity, rule, next_expires_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id`
+
+	var id uuid.UUID
+	err := tx.QueryRow(
+		stmt,
+		s.Title,
+		s.Description,
+		s.Category,
+		s.ResolverType,
+		s.GroupID,
+		s.DisputeWindowHours,
+		s.Liquidity,
+		s.Rule,
+		s.NextExpiresAt,
+		s.CreatedBy,
+	).Scan(&id)
+
+	return id, err
+}
+
+func (m *SeriesModel) Get(id uuid.UUID) (Series, error) {
+	stmt := `SELECT
+		s.id,
+		s.title,
+		s.description,
+		s.category,
+		s.resolver_type,
+		s.group_id,
+		s.dispute_window_hours,
+		s.liquidity,
+		s.rule,
+		s.next_expires_at,
+		s.instances,
+		s.created_by,
+		s.stopped_at,
+		s.stop_reason,
+		s.created_at,
+		u.username,
+		g.name
+	FROM market_series s
+	JOIN users u ON u.id = s.created_by
+	LEFT JOIN groups g ON g.id = s.group_id
+	WHERE s.id = $1`
+
+	var s Series
+	err := m.DB.QueryRow(stmt, id).Scan(
+		&s.ID,
+		&s.Title,
+		&s.Description,
+		&s.Category,
+		&s.ResolverType,
+		&s.GroupID,
+		&s.DisputeWindowHours,
+		&s.Liquidity,
+		&s.Rule,
+		&s.NextExpiresAt,
+		&s.Instances,
+		&s.CreatedBy,
+		&s.StoppedAt,
+		&s.StopReason,
+		&s.CreatedAt,
+		&s.CreatorUsername,
+		&s.GroupName,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Series{}, ErrNoRecord
+		}
+		return Series{}, err
+	}
+
+	return s, nil
+}
+
+// Due lists up to limit running series whose latest market has expired, or
+// that have none yet, the longest overdue first. Only the fields needed to
+// create the next market are set.
+func (m *SeriesModel) Due(limit int) ([]Series, error) {
+	stmt := `SELECT id, title, description, category, resolver_type, group_id, dispute_window_hours, liquidity, rule, next_expires_at, instances, created_by
+	FROM market_series s
+	WHERE stopped_at IS NULL
+	  AND NOT EXISTS (SELECT 1 FROM markets WHERE series_id = s.id AND expires_at > NOW())
+	ORDER BY next_expires_at
+	LIMIT $1`
+
+	rows, err := m.DB.Query(stmt, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var series []Series
+
+	for rows.Next() {
+		var s Series
+		err = rows.Scan(
+			&s.ID,
+			&s.Title,
+			&s.Description,
+			&s.Category,
+			&s.ResolverType,
+			&s.GroupID,
+			&s.DisputeWindowHours,
+			&s.Liquidity,
+			&s.Rule,
+			&s.NextExpiresAt,
+			&s.Instances,
+			&s.CreatedBy,
+		)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+// Advance records that another market was created and when the one after
+// it will expire.
+func (m *SeriesModel) Advance(tx *sql.Tx, id uuid.UUID, nextExpiresAt time.Time) error {
+	stmt := `UPDATE market_series SET instances = instances + 1, next_expires_at = $2 WHERE id = $1`
+	_, err := tx.Exec(stmt, id, nextExpiresAt)
+	return err
+}
+
+// Stop ends a running series, recording why unless reason is empty. Its
+// existing markets are left alone.
+func (m *SeriesModel) Stop(id uuid.UUID, reason string) error {
+	stmt := `UPDATE market_series SET stopped_at = NOW(), stop_reason = NULLIF($2, '') WHERE id = $1 AND stopped_at IS NULL`
+	_, err := m.DB.Exec(stmt, id, reason)
+	return err
+}
+
+func (m *SeriesModel) Stats(id uuid.UUID) (SeriesStats, error) {
+	stmt := `SELECT
+		COUNT(*),
+		COUNT(*) FILTER (WHERE m.status = 'open'),
+		COUNT(*) FILTER (WHERE m.status = 'resolved'),
+		COUNT(*) FILTER (WHERE m.status = 'void'),
+		COALESCE(SUM(p.pool), 0)
+	FROM markets m
+	LEFT JOIN (SELECT market_id, SUM(pool_amount) AS pool FROM outcomes GROUP BY market_id) p ON p.market_id = m.id
+	WHERE m.series_id = $1
+	  AND m.hidden_at IS NULL`
+
+	var stats SeriesStats
+	err := m.DB.QueryRow(stmt, id).Scan(&stats.Markets, &stats.Open, &stats.Resolved, &stats.Voided, &stats.Volume)
+	if err != nil {
+		return SeriesStats{}, err
+	}
+
+	stmt = `SELECT o.label, COUNT(*)
+	FROM markets m
+	JOIN outcomes o ON o.id = m.resolved_outcome_id
+	WHERE m.series_id = $1
+	  AND m.status = 'resolved'
+	  AND m.hidden_at IS NULL
+	GROUP BY o.label
+	ORDER BY COUNT(*) DESC, o.label`
+
+	rows, err := m.DB.Query(stmt, id)
+	if err != nil {
+		return SeriesStats{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a SeriesAnswer
+		err = rows.Scan(&a.Label, &a.Count)
+		if err != nil {
+			return SeriesStats{}, err
+		}
+		if stats.Resolved > 0 {
+			a.Percent = a.Count * 100 / stats.Resolved
+		}
+		stats.Answers = append(stats.Answers, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return SeriesStats{}, err
+	}
+
+	return stats, nil
+}
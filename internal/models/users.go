@@ -11,6 +11,19 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+// CanModerate reports whether the role may remove other users' content.
+func (r Role) CanModerate() bool {
+	return r == RoleModerator || r == RoleAdmin
+}
+
 type User struct {
 	ID             uuid.UUID
 	Username       string
@@ -18,6 +31,12 @@ type User struct {
 	HashedPassword []byte
 	LastClaimedAt  sql.NullTime
 	Balance        int
+	PublicBets     bool
+	Role           Role
+	RewardStreak   int
+	Timezone       string
+	SuspendedAt    *time.Time
+	CreatedAt      time.Time
 }
 
 type UserModel struct {
@@ -66,6 +85,10 @@ func (m *UserModel) Authenticate(email, password string) (uuid.UUID, error) {
 		return uuid.UUID{}, err
 	}
 
+	if hashedPassword == nil {
+		return uuid.UUID{}, ErrInvalidCredentials
+	}
+
 	err = bcrypt.CompareHashAndPassword(hashedPassword, []byte(password))
 	if err != nil {
 		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
@@ -78,6 +101,85 @@ func (m *UserModel) Authenticate(email, password string) (uuid.UUID, error) {
 	return id, nil
 }
 
+func (m *UserModel) InsertExternal(tx *sql.Tx, username, email string) (uuid.UUID, error) {
+	stmt := `INSERT INTO users (username, email, balance) VALUES ($1, $2, $3) RETURNING id`
+
+	var id uuid.UUID
+	err := tx.QueryRow(stmt, username, email, initialBalance).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == "23505" {
+				if strings.Contains(pgErr.ConstraintName, "username") {
+					return uuid.UUID{}, ErrUsernameAlreadyExists
+				} else if strings.Contains(pgErr.ConstraintName, "email") {
+					return uuid.UUID{}, ErrEmailAlreadyExists
+				}
+			}
+		}
+		return uuid.UUID{}, err
+	}
+
+	return id, nil
+}
+
+func (m *UserModel) IDForIdentity(issuer, subject string) (uuid.UUID, error) {
+	var id uuid.UUID
+	stmt := `SELECT user_id FROM user_identities WHERE issuer = $1 AND subject = $2`
+
+	err := m.DB.QueryRow(stmt, issuer, subject).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.UUID{}, ErrNoRecord
+		}
+
+		return uuid.UUID{}, err
+	}
+
+	return id, nil
+}
+
+func (m *UserModel) IDForEmail(tx *sql.Tx, email string) (uuid.UUID, error) {
+	var id uuid.UUID
+	stmt := `SELECT id FROM users WHERE lower(email) = lower($1)`
+
+	err := tx.QueryRow(stmt, email).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.UUID{}, ErrNoRecord
+		}
+
+		return uuid.UUID{}, err
+	}
+
+	return id, nil
+}
+
+func (m *UserModel) UsernameExists(tx *sql.Tx, username string) (bool, error) {
+	var exists bool
+	stmt := "SELECT EXISTS(SELECT true FROM users WHERE username = $1)"
+
+	err := tx.QueryRow(stmt, username).Scan(&exists)
+
+	return exists, err
+}
+
+func (m *UserModel) LinkIdentity(tx *sql.Tx, userID uuid.UUID, issuer, subject string) error {
+	stmt := `INSERT INTO user_identities (user_id, issuer, subject) VALUES ($1, $2, $3)`
+	_, err := tx.Exec(stmt, userID, issuer, subject)
+	if err != nil {
+		var pgErr *pgconn.PgError
+
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrIdentityAlreadyLinked
+		}
+		return err
+	}
+
+	return nil
+}
+
 func (m *UserModel) Exists(id uuid.UUID) (bool, error) {
 	var exists bool
 	stmt := "SELECT EXISTS(SELECT true FROM users WHERE id = $1)"
@@ -87,27 +189,110 @@ func (m *UserModel) Exists(id uuid.UUID) (bool, error) {
 	return exists, err
 }
 
-func (m *UserModel) GetTemplateInfo(id uuid.UUID) (int, sql.NullTime, error) {
-	var balance int
-	var lastClaimedAt sql.NullTime
-	stmt := "SELECT balance, last_daily_claim FROM users WHERE id = $1"
-	err := m.DB.QueryRow(stmt, id).Scan(&balance, &lastClaimedAt)
+func (m *UserModel) Get(id uuid.UUID) (User, error) {
+	var user User
+	stmt := `SELECT id, username, email, public_bets, timezone, created_at FROM users WHERE id = $1`
+
+	err := m.DB.QueryRow(stmt, id).Scan(&user.ID, &user.Username, &user.Email, &user.PublicBets, &user.Timezone, &user.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return User{}, ErrNoRecord
+		}
+
+		return User{}, err
+	}
+
+	return user, nil
+}
+
+func (m *UserModel) GetByUsername(username string) (User, error) {
+	var user User
+	stmt := `SELECT id, username, public_bets, created_at FROM users WHERE username = $1`
+
+	err := m.DB.QueryRow(stmt, username).Scan(&user.ID, &user.Username, &user.PublicBets, &user.CreatedAt)
 	if err != nil {
-		return 0, sql.NullTime{}, err
+		if errors.Is(err, sql.ErrNoRows) {
+			return User{}, ErrNoRecord
+		}
+
+		return User{}, err
 	}
 
-	return balance, lastClaimedAt, nil
+	return user, nil
+}
+
+func (m *UserModel) UpdateSettings(id uuid.UUID, publicBets bool, timezone string) error {
+	stmt := `UPDATE users SET public_bets = $1, timezone = $2 WHERE id = $3`
+	_, err := m.DB.Exec(stmt, publicBets, timezone, id)
+	return err
+}
+
+func (m *UserModel) GetTemplateInfo(id uuid.UUID) (User, error) {
+	var user User
+	stmt := "SELECT id, balance, last_daily_claim, role, reward_streak, timezone FROM users WHERE id = $1"
+	err := m.DB.QueryRow(stmt, id).Scan(&user.ID, &user.Balance, &user.LastClaimedAt, &user.Role, &user.RewardStreak, &user.Timezone)
+	if err != nil {
+		return User{}, err
+	}
+
+	return user, nil
+}
+
+func (m *UserModel) IsAdmin(id uuid.UUID) (bool, error) {
+	var isAdmin bool
+	stmt := "SELECT EXISTS(SELECT true FROM users WHERE id = $1 AND role = $2)"
+
+	err := m.DB.QueryRow(stmt, id, RoleAdmin).Scan(&isAdmin)
+
+	return isAdmin, err
+}
+
+func (m *UserModel) Role(id uuid.UUID) (Role, error) {
+	var role Role
+	stmt := "SELECT role FROM users WHERE id = $1"
+
+	err := m.DB.QueryRow(stmt, id).Scan(&role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNoRecord
+		}
+
+		return "", err
+	}
+
+	return role, nil
+}
+
+func (m *UserModel) IsSuspended(id uuid.UUID) (bool, error) {
+	var suspended bool
+	stmt := "SELECT EXISTS(SELECT true FROM users WHERE id = $1 AND suspended_at IS NOT NULL)"
+
+	err := m.DB.QueryRow(stmt, id).Scan(&suspended)
+
+	return suspended, err
+}
+
+func (m *UserModel) Suspend(tx *sql.Tx, id uuid.UUID, reason string) error {
+	stmt := `UPDATE users SET suspended_at = NOW(), suspended_reason = $1 WHERE id = $2 AND suspended_at IS NULL`
+	_, err := tx.Exec(stmt, reason, id)
+	return err
+}
+
+func (m *UserModel) ResetBalances(tx *sql.Tx, balance int) error {
+	stmt := `UPDATE users SET balance = $1`
+	_, err := tx.Exec(stmt, balance)
+	return err
 }
 
 func (m *UserModel) SelectForUpdate(tx *sql.Tx, id uuid.UUID) (User, error) {
 	var user User
-	stmt := `SELECT id, balance, last_daily_claim
+	stmt := `SELECT id, balance, last_daily_claim, reward_streak, timezone, suspended_at
 			FROM users
 			WHERE id = $1
 			FOR UPDATE
 			`
 
-	err := tx.QueryRow(stmt, id).Scan(&user.ID, &user.Balance, &user.LastClaimedAt)
+	err := tx.QueryRow(stmt, id).Scan(&user.ID, &user.Balance, &user.LastClaimedAt, &user.RewardStreak, &user.Timezone, &user.SuspendedAt)
 	if err != nil {
 		return User{}, err
 	}
@@ -115,9 +300,9 @@ func (m *UserModel) SelectForUpdate(tx *sql.Tx, id uuid.UUID) (User, error) {
 	return user, nil
 }
 
-func (m *UserModel) ApplyDailyClaim(tx *sql.Tx, id uuid.UUID, balance int, lastClaimedAt time.Time) error {
-	stmt := `UPDATE users SET balance = $1, last_daily_claim = $2 WHERE id = $3`
-	_, err := tx.Exec(stmt, balance, lastClaimedAt, id)
+func (m *UserModel) ApplyDailyClaim(tx *sql.Tx, id uuid.UUID, balance int, lastClaimedAt time.Time, streak int) error {
+	stmt := `UPDATE users SET balance = $1, last_daily_claim = $2, reward_streak = $3 WHERE id = $4`
+	_, err := tx.Exec(stmt, balance, lastClaimedAt, streak, id)
 	if err != nil {
 		return err
 	}
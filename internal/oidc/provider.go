@@ -0,0 +1,174 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrDiscoveryFailed = errors.New("oidc: could not load the provider discovery document")
+	ErrExchangeFailed  = errors.New("oidc: authorization code exchange failed")
+	ErrMissingIDToken  = errors.New("oidc: token response did not contain an id_token")
+	ErrIssuerMismatch  = errors.New("oidc: discovery issuer does not match the configured issuer")
+)
+
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type Provider struct {
+	config   Config
+	client   *http.Client
+	metadata metadata
+
+	mu   sync.Mutex
+	keys map[string]*rsa.PublicKey
+}
+
+// NewProvider fetches the discovery document published under
+// {issuer}/.well-known/openid-configuration. A nil client falls back to a
+// client with a short timeout so a slow provider cannot hang the login flow.
+func NewProvider(ctx context.Context, config Config, client *http.Client) (*Provider, error) {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	p := &Provider{
+		config: config,
+		client: client,
+	}
+
+	discoveryURL := strings.TrimSuffix(config.Issuer, "/") + "/.well-known/openid-configuration"
+	err := p.getJSON(ctx, discoveryURL, &p.metadata)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDiscoveryFailed, err)
+	}
+
+	if strings.TrimSuffix(p.metadata.Issuer, "/") != strings.TrimSuffix(config.Issuer, "/") {
+		return nil, ErrIssuerMismatch
+	}
+
+	if p.metadata.AuthorizationEndpoint == "" || p.metadata.TokenEndpoint == "" || p.metadata.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: missing endpoints", ErrDiscoveryFailed)
+	}
+
+	return p, nil
+}
+
+func (p *Provider) Issuer() string {
+	return p.metadata.Issuer
+}
+
+// AuthCodeURL builds the authorization request for the code flow. The
+// verifier is never sent here, only its S256 challenge.
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) string {
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", p.config.ClientID)
+	v.Set("redirect_uri", p.config.RedirectURL)
+	v.Set("scope", strings.Join(p.config.Scopes, " "))
+	v.Set("state", state)
+	v.Set("nonce", nonce)
+	v.Set("code_challenge", CodeChallenge(verifier))
+	v.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(p.metadata.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+
+	return p.metadata.AuthorizationEndpoint + sep + v.Encode()
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Exchange redeems the authorization code and returns the verified claims of
+// the ID token issued alongside it.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (Claims, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Claims{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrExchangeFailed, err)
+	}
+	defer res.Body.Close()
+
+	var tr tokenResponse
+	err = json.NewDecoder(res.Body).Decode(&tr)
+	if err != nil {
+		return Claims{}, fmt.Errorf("%w: %v", ErrExchangeFailed, err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return Claims{}, fmt.Errorf("%w: %s %s", ErrExchangeFailed, tr.Error, tr.ErrorDescription)
+	}
+
+	if tr.IDToken == "" {
+		return Claims{}, ErrMissingIDToken
+	}
+
+	return p.VerifyIDToken(ctx, tr.IDToken, nonce)
+}
+
+func (p *Provider) getJSON(ctx context.Context, u string, dst any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", res.StatusCode, u)
+	}
+
+	return json.NewDecoder(res.Body).Decode(dst)
+}
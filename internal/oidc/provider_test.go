@@ -0,0 +1,297 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const (
+	testClientID = "foresee"
+	testKeyID    = "key-1"
+)
+
+// mockProvider is a local stand-in for an OpenID Connect provider. It
+// issues an ID token with claims for the code "valid-code", provided the
+// PKCE verifier matches the challenge it was last sent.
+type mockProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	// issuer, when set, is announced in the discovery document instead of
+	// the server's own URL.
+	issuer string
+
+	challenge string
+	claims    map[string]any
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mp := &mockProvider{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		issuer := mp.server.URL
+		if mp.issuer != "" {
+			issuer = mp.issuer
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer,
+			"authorization_endpoint": mp.server.URL + "/authorize",
+			"token_endpoint":         mp.server.URL + "/token",
+			"jwks_uri":               mp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": testKeyID,
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "valid-code" || CodeChallenge(r.FormValue("code_verifier")) != mp.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{"id_token": mp.sign(t, testKeyID, mp.claims)})
+	})
+
+	mp.server = httptest.NewServer(mux)
+	t.Cleanup(mp.server.Close)
+
+	return mp
+}
+
+func (mp *mockProvider) sign(t *testing.T, kid string, claims map[string]any) string {
+	t.Helper()
+
+	h, err := json.Marshal(map[string]string{"alg": "RS256", "kid": kid})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	digest := sha256.Sum256([]byte(signed))
+
+	sig, err := rsa.SignPKCS1v15(rand.Reader, mp.key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// validClaims are the claims of a token the provider issued for the test
+// client with nonce.
+func (mp *mockProvider) validClaims(nonce string) map[string]any {
+	return map[string]any{
+		"iss":                mp.server.URL,
+		"sub":                "user-123",
+		"aud":                testClientID,
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"iat":                time.Now().Unix(),
+		"nonce":              nonce,
+		"email":              "ana@example.com",
+		"email_verified":     true,
+		"preferred_username": "ana",
+	}
+}
+
+func (mp *mockProvider) provider(t *testing.T) *Provider {
+	t.Helper()
+
+	p, err := NewProvider(context.Background(), Config{
+		Issuer:      mp.server.URL,
+		ClientID:    testClientID,
+		RedirectURL: "http://localhost:4000/auth/oidc/callback",
+	}, mp.server.Client())
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+
+	return p
+}
+
+func TestNewProvider(t *testing.T) {
+	mp := newMockProvider(t)
+
+	_, err := NewProvider(context.Background(), Config{Issuer: mp.server.URL + "/other", ClientID: testClientID}, mp.server.Client())
+	if !errors.Is(err, ErrDiscoveryFailed) {
+		t.Errorf("NewProvider() without a discovery document error = %v, want ErrDiscoveryFailed", err)
+	}
+
+	mp.issuer = "https://accounts.example.com"
+	_, err = NewProvider(context.Background(), Config{Issuer: mp.server.URL, ClientID: testClientID}, mp.server.Client())
+	if !errors.Is(err, ErrIssuerMismatch) {
+		t.Errorf("NewProvider() for another issuer error = %v, want ErrIssuerMismatch", err)
+	}
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	mp := newMockProvider(t)
+	p := mp.provider(t)
+
+	u, err := url.Parse(p.AuthCodeURL("state-1", "nonce-1", "verifier-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := u.Scheme + "://" + u.Host + u.Path; got != mp.server.URL+"/authorize" {
+		t.Errorf("endpoint = %s, want %s/authorize", got, mp.server.URL)
+	}
+
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             testClientID,
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"code_challenge":        CodeChallenge("verifier-1"),
+		"code_challenge_method": "S256",
+		"code_verifier":         "",
+	}
+	for k, v := range want {
+		if got := u.Query().Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+}
+
+func TestExchange(t *testing.T) {
+	mp := newMockProvider(t)
+	p := mp.provider(t)
+
+	tests := []struct {
+		name     string
+		code     string
+		verifier string
+		nonce    string
+		claims   func(map[string]any)
+		wantErr  error
+	}{
+		{name: "Valid"},
+		{
+			name:   "Email verified as a string",
+			claims: func(c map[string]any) { c["email_verified"] = "true" },
+		},
+		{name: "Unknown code", code: "other-code", wantErr: ErrExchangeFailed},
+		{name: "Wrong verifier", verifier: "other-verifier", wantErr: ErrExchangeFailed},
+		{name: "Wrong nonce", nonce: "other-nonce", wantErr: ErrNonceMismatch},
+		{
+			name:    "Other audience",
+			claims:  func(c map[string]any) { c["aud"] = "someone-else" },
+			wantErr: ErrInvalidClaims,
+		},
+		{
+			name:    "Other issuer",
+			claims:  func(c map[string]any) { c["iss"] = "https://accounts.example.com" },
+			wantErr: ErrInvalidClaims,
+		},
+		{
+			name:    "Expired",
+			claims:  func(c map[string]any) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name:    "No subject",
+			claims:  func(c map[string]any) { delete(c, "sub") },
+			wantErr: ErrInvalidClaims,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mp.challenge = CodeChallenge("verifier")
+			mp.claims = mp.validClaims("nonce")
+			if tt.claims != nil {
+				tt.claims(mp.claims)
+			}
+
+			code, verifier, nonce := "valid-code", "verifier", "nonce"
+			if tt.code != "" {
+				code = tt.code
+			}
+			if tt.verifier != "" {
+				verifier = tt.verifier
+			}
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+
+			claims, err := p.Exchange(context.Background(), code, verifier, nonce)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Exchange() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Exchange() error = %v", err)
+			}
+			if claims.Subject != "user-123" || claims.Email != "ana@example.com" || !bool(claims.EmailVerified) {
+				t.Errorf("Exchange() = %+v", claims)
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenSignature(t *testing.T) {
+	mp := newMockProvider(t)
+	p := mp.provider(t)
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forger := &mockProvider{server: mp.server, key: other}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr error
+	}{
+		{name: "Signed with another key", token: forger.sign(t, testKeyID, mp.validClaims("nonce")), wantErr: ErrInvalidSignature},
+		{name: "Unknown key", token: mp.sign(t, "key-2", mp.validClaims("nonce")), wantErr: ErrUnknownKey},
+		{name: "Malformed", token: "not-a-token", wantErr: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := p.VerifyIDToken(context.Background(), tt.token, "nonce")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyIDToken() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken     = errors.New("oidc: malformed id token")
+	ErrUnsupportedAlg   = errors.New("oidc: unsupported id token signing algorithm")
+	ErrUnknownKey       = errors.New("oidc: id token signed with an unknown key")
+	ErrInvalidSignature = errors.New("oidc: invalid id token signature")
+	ErrInvalidClaims    = errors.New("oidc: id token claims are not valid for this client")
+	ErrTokenExpired     = errors.New("oidc: id token has expired")
+	ErrNonceMismatch    = errors.New("oidc: id token nonce does not match")
+)
+
+const clockSkew = time.Minute
+
+type Claims struct {
+	Issuer            string       `json:"iss"`
+	Subject           string       `json:"sub"`
+	Audience          audience     `json:"aud"`
+	AuthorizedParty   string       `json:"azp"`
+	Expiry            int64        `json:"exp"`
+	IssuedAt          int64        `json:"iat"`
+	Nonce             string       `json:"nonce"`
+	Email             string       `json:"email"`
+	EmailVerified     flexibleBool `json:"email_verified"`
+	PreferredUsername string       `json:"preferred_username"`
+	Name              string       `json:"name"`
+}
+
+// audience accepts both the single string and the array form of "aud".
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var single string
+	if err := json.Unmarshal(b, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// flexibleBool accepts "email_verified" as either a JSON boolean or the
+// string "true", which some providers send.
+type flexibleBool bool
+
+func (f *flexibleBool) UnmarshalJSON(b []byte) error {
+	var v bool
+	if err := json.Unmarshal(b, &v); err == nil {
+		*f = flexibleBool(v)
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	*f = flexibleBool(strings.EqualFold(s, "true"))
+	return nil
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+func (p *Provider) VerifyIDToken(ctx context.Context, raw, nonce string) (Claims, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return Claims{}, ErrInvalidToken
+	}
+
+	var h header
+	err := decodeSegment(parts[0], &h)
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+
+	if h.Alg != "RS256" {
+		return Claims{}, ErrUnsupportedAlg
+	}
+
+	key, err := p.key(ctx, h.Kid)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	err = rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig)
+	if err != nil {
+		return Claims{}, ErrInvalidSignature
+	}
+
+	var c Claims
+	err = decodeSegment(parts[1], &c)
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+
+	if strings.TrimSuffix(c.Issuer, "/") != strings.TrimSuffix(p.metadata.Issuer, "/") {
+		return Claims{}, ErrInvalidClaims
+	}
+
+	if !slices.Contains(c.Audience, p.config.ClientID) {
+		return Claims{}, ErrInvalidClaims
+	}
+
+	if len(c.Audience) > 1 && c.AuthorizedParty != p.config.ClientID {
+		return Claims{}, ErrInvalidClaims
+	}
+
+	if c.Subject == "" {
+		return Claims{}, ErrInvalidClaims
+	}
+
+	if time.Now().Add(-clockSkew).After(time.Unix(c.Expiry, 0)) {
+		return Claims{}, ErrTokenExpired
+	}
+
+	if c.Nonce != nonce {
+		return Claims{}, ErrNonceMismatch
+	}
+
+	return c, nil
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// key returns the signing key for kid, refetching the key set once when the
+// kid is unknown so that provider key rotation is picked up.
+func (p *Provider) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if k, ok := p.lookup(kid); ok {
+		return k, nil
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	err := p.getJSON(ctx, p.metadata.JWKSURI, &set)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+
+		pub, err := k.rsaPublicKey()
+		if err != nil {
+			return nil, err
+		}
+		keys[k.Kid] = pub
+	}
+	p.keys = keys
+
+	if k, ok := p.lookup(kid); ok {
+		return k, nil
+	}
+
+	return nil, ErrUnknownKey
+}
+
+func (p *Provider) lookup(kid string) (*rsa.PublicKey, bool) {
+	if k, ok := p.keys[kid]; ok {
+		return k, true
+	}
+
+	if kid == "" && len(p.keys) == 1 {
+		for _, k := range p.keys {
+			return k, true
+		}
+	}
+
+	return nil, false
+}
+
+func (k jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: invalid jwk modulus: %w", err)
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: invalid jwk exponent: %w", err)
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+func decodeSegment(seg string, dst any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, dst)
+}
@@ -0,0 +1,202 @@
+// Package oracle reads the numbers markets with the oracle resolver type are
+// answered from. A market is configured with a data source, such as an HTTP
+// JSON endpoint or a script installed on the server, a path to the number in
+// what the source returns and, for yes/no markets, the comparison that
+// decides between yes and no.
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var ErrUnknownSource = errors.New("the data source must be an HTTP endpoint or a script")
+var ErrInvalidOperator = errors.New("the comparison must be one of >, >=, <, <=, = or !=")
+var ErrNoValue = errors.New("the data source did not return a number at the path")
+
+// Kind names a type of data source, see Sources.
+type Kind string
+
+const (
+	KindHTTP   Kind = "http"
+	KindScript Kind = "script"
+)
+
+func AllKinds() []Kind {
+	return []Kind{
+		KindHTTP,
+		KindScript,
+	}
+}
+
+// Operator compares a reading with a market's threshold. Yes/no markets
+// resolve yes when the comparison holds.
+type Operator string
+
+const (
+	Above    Operator = ">"
+	AtLeast  Operator = ">="
+	Below    Operator = "<"
+	AtMost   Operator = "<="
+	Equal    Operator = "="
+	NotEqual Operator = "!="
+)
+
+func AllOperators() []Operator {
+	return []Operator{
+		Above,
+		AtLeast,
+		Below,
+		AtMost,
+		Equal,
+		NotEqual,
+	}
+}
+
+// Holds reports whether value compares to threshold as o says.
+func (o Operator) Holds(value, threshold float64) bool {
+	switch o {
+	case Above:
+		return value > threshold
+	case AtLeast:
+		return value >= threshold
+	case Below:
+		return value < threshold
+	case AtMost:
+		return value <= threshold
+	case Equal:
+		return value == threshold
+	case NotEqual:
+		return value != threshold
+	}
+
+	return false
+}
+
+// Config is how a market is answered: Target is read from the Source kind
+// and Path leads to the number in the JSON it returns. Operator and
+// Threshold are left empty for markets answered with the number itself.
+type Config struct {
+	Source    Kind
+	Target    string
+	Path      string
+	Operator  Operator
+	Threshold float64
+}
+
+// Source returns the reading a market is answered from, a JSON document or
+// a bare number.
+type Source interface {
+	Read(ctx context.Context) ([]byte, error)
+}
+
+// Opener builds the Source reading target, or rejects a target it cannot
+// read.
+type Opener func(target string) (Source, error)
+
+// Sources are the kinds of data source markets can be configured with.
+// Register another Opener under a kind to add a source or, in tests, to
+// stand in for one.
+type Sources map[Kind]Opener
+
+// Default returns the HTTP sources, fetched with client, and the script
+// sources. Scripts are only run from scriptDir, and none are allowed when it
+// is empty.
+func Default(client *http.Client, scriptDir string) Sources {
+	return Sources{
+		KindHTTP:   HTTP(client),
+		KindScript: Scripts(scriptDir),
+	}
+}
+
+func (s Sources) open(kind Kind, target string) (Source, error) {
+	open, ok := s[kind]
+	if !ok {
+		return nil, ErrUnknownSource
+	}
+
+	return open(target)
+}
+
+// Check validates cfg without reading from its source, for when a market is
+// created. needsRule is set for markets that need a comparison to answer.
+func (s Sources) Check(cfg Config, needsRule bool) error {
+	_, err := s.open(cfg.Source, cfg.Target)
+	if err != nil {
+		return err
+	}
+
+	_, err = ParsePath(cfg.Path)
+	if err != nil {
+		return err
+	}
+
+	if needsRule && !slices.Contains(AllOperators(), cfg.Operator) {
+		return ErrInvalidOperator
+	}
+
+	return nil
+}
+
+// Read fetches cfg's source and returns the number at its path.
+func (s Sources) Read(ctx context.Context, cfg Config) (float64, error) {
+	path, err := ParsePath(cfg.Path)
+	if err != nil {
+		return 0, err
+	}
+
+	source, err := s.open(cfg.Source, cfg.Target)
+	if err != nil {
+		return 0, err
+	}
+
+	body, err := source.Read(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return Value(body, path)
+}
+
+// Value decodes body as JSON and returns the number at path. Numeric
+// strings are accepted, and booleans read as 1 and 0.
+func Value(body []byte, path Path) (float64, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var doc any
+	err := dec.Decode(&doc)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrNoValue, err)
+	}
+
+	v, ok := path.Lookup(doc)
+	if !ok {
+		return 0, fmt.Errorf("%w: nothing at %s", ErrNoValue, path)
+	}
+
+	switch v := v.(type) {
+	case json.Number:
+		return v.Float64()
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q at %s", ErrNoValue, v, path)
+		}
+		return f, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	return 0, fmt.Errorf("%w: %s is not a number", ErrNoValue, path)
+}
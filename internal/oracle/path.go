@@ -0,0 +1,130 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidPath = errors.New("the path must look like $.data.price, $.items[0].value or $['last price']")
+
+// Path is the subset of JSONPath that picks a single value: object keys
+// after a dot or in quoted brackets, and array indexes in brackets. The
+// empty path and "$" are the whole document.
+type Path []step
+
+type step struct {
+	key   string
+	index int
+	array bool
+}
+
+// ParsePath reads a path such as $.data.price or $.items[0]['last price'].
+func ParsePath(text string) (Path, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+
+	rest, ok := strings.CutPrefix(text, "$")
+	if !ok {
+		return nil, ErrInvalidPath
+	}
+
+	var path Path
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+
+			if end == 0 {
+				return nil, ErrInvalidPath
+			}
+
+			path = append(path, step{key: rest[:end]})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, ErrInvalidPath
+			}
+
+			s, err := bracket(rest[1:end])
+			if err != nil {
+				return nil, err
+			}
+
+			path = append(path, s)
+			rest = rest[end+1:]
+		default:
+			return nil, ErrInvalidPath
+		}
+	}
+
+	return path, nil
+}
+
+// bracket reads what is between square brackets, a quoted key or an index.
+func bracket(inner string) (step, error) {
+	if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+		return step{key: inner[1 : len(inner)-1]}, nil
+	}
+
+	index, err := strconv.Atoi(inner)
+	if err != nil || index < 0 {
+		return step{}, ErrInvalidPath
+	}
+
+	return step{index: index, array: true}, nil
+}
+
+// Lookup follows the path through a document decoded by encoding/json.
+func (p Path) Lookup(doc any) (any, bool) {
+	v := doc
+	for _, s := range p {
+		if s.array {
+			items, ok := v.([]any)
+			if !ok || s.index >= len(items) {
+				return nil, false
+			}
+			v = items[s.index]
+			continue
+		}
+
+		fields, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		v, ok = fields[s.key]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+func (p Path) String() string {
+	var b strings.Builder
+	b.WriteString("$")
+
+	for _, s := range p {
+		switch {
+		case s.array:
+			fmt.Fprintf(&b, "[%d]", s.index)
+		case strings.ContainsAny(s.key, ".[]' "):
+			fmt.Fprintf(&b, "[%q]", s.key)
+		default:
+			b.WriteString(".")
+			b.WriteString(s.key)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,244 @@
+package oracle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var ErrInvalidURL = errors.New("the endpoint must be an http or https URL")
+var ErrInvalidScript = errors.New("the script must be one of the oracle scripts installed on the server")
+var ErrPrivateAddress = errors.New("the endpoint must be on a public address")
+
+// maxReading bounds how much of a response or script output is read.
+const maxReading = 1 << 20
+
+// HTTPSource GETs a JSON document from URL.
+type HTTPSource struct {
+	URL    string
+	Client *http.Client
+}
+
+// notPublic lists the special-purpose ranges netip does not classify
+// itself: shared address space, IETF protocol assignments, benchmarking
+// networks and the NAT64 prefixes that map onto IPv4.
+var notPublic = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+// IsPublic reports whether ip is an address on the public internet, as
+// opposed to loopback, private, link-local (which includes cloud metadata
+// services) or another special-purpose address.
+func IsPublic(ip netip.Addr) bool {
+	ip = ip.Unmap()
+
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+
+	for _, p := range notPublic {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// PublicClient returns an HTTP client that only connects to public
+// addresses, see IsPublic. The check is made on every connection once the
+// host name has been resolved, so it covers each redirect and cannot be
+// sidestepped with a host name pointing at an internal address. Proxies
+// from the environment are not used.
+func PublicClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			ip, err := netip.ParseAddr(host)
+			if err != nil || !IsPublic(ip) {
+				return fmt.Errorf("%s: %w", host, ErrPrivateAddress)
+			}
+
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+// HTTP opens HTTPSources fetched with client, or http.DefaultClient when it
+// is nil. Any http or https URL is accepted here: which hosts can actually
+// be reached is up to client, see PublicClient.
+func HTTP(client *http.Client) Opener {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return func(target string) (Source, error) {
+		u, err := url.Parse(target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, ErrInvalidURL
+		}
+
+		return HTTPSource{URL: target, Client: client}, nil
+	}
+}
+
+func (s HTTPSource) Read(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := s.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s answered %s", s.URL, res.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxReading+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(body) > maxReading {
+		return nil, fmt.Errorf("%s answered with more than %d bytes", s.URL, maxReading)
+	}
+
+	return body, nil
+}
+
+// ScriptSource runs the executable at Path without arguments and reads its
+// standard output.
+type ScriptSource struct {
+	Path string
+}
+
+// Scripts opens ScriptSources for the executables directly inside dir,
+// targeted by file name. Creators can only pick among the scripts an
+// operator installed there.
+func Scripts(dir string) Opener {
+	return func(target string) (Source, error) {
+		if dir == "" || target == "" || target != filepath.Base(target) || strings.HasPrefix(target, ".") {
+			return nil, ErrInvalidScript
+		}
+
+		path := filepath.Join(dir, target)
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+			return nil, ErrInvalidScript
+		}
+
+		return ScriptSource{Path: path}, nil
+	}
+}
+
+// Read runs the script until it exits. At most maxReading bytes of its
+// output are kept, and a script printing more is stopped.
+func (s ScriptSource) Read(ctx context.Context) ([]byte, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, s.Path)
+	cmd.Dir = filepath.Dir(s.Path)
+
+	// Children the script left behind may hold its output open after it
+	// was stopped, so Wait stops waiting for them after a while.
+	cmd.WaitDelay = time.Second
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &limitedWriter{w: &stderr, n: maxErrorOutput}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(s.Path), err)
+	}
+
+	body, readErr := io.ReadAll(io.LimitReader(stdout, maxReading+1))
+	if len(body) > maxReading {
+		cancel()
+		cmd.Wait()
+		return nil, fmt.Errorf("%s printed more than %d bytes", filepath.Base(s.Path), maxReading)
+	}
+
+	err = cmd.Wait()
+	if err == nil {
+		err = readErr
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %w: %s", filepath.Base(s.Path), err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", filepath.Base(s.Path), err)
+	}
+
+	return body, nil
+}
+
+// maxErrorOutput bounds how much of a script's standard error is kept for
+// the failure message.
+const maxErrorOutput = 4 << 10
+
+// limitedWriter keeps the first n bytes written to it and discards the
+// rest, so a chatty script cannot fill memory through its error output.
+type limitedWriter struct {
+	w io.Writer
+	n int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.n > 0 {
+		keep := p
+		if len(keep) > l.n {
+			keep = keep[:l.n]
+		}
+		n, err := l.w.Write(keep)
+		l.n -= n
+		if err != nil {
+			return n, err
+		}
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,214 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// standIn serves body with status from a local server for the duration of
+// the test.
+func standIn(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestReadHTTP(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		path    string
+		want    float64
+		wantErr bool
+	}{
+		{name: "Number", status: http.StatusOK, body: `{"data": {"price": 3120.5}}`, path: "$.data.price", want: 3120.5},
+		{name: "Numeric string", status: http.StatusOK, body: `{"rates": [{"eur": "0.92"}]}`, path: "$.rates[0].eur", want: 0.92},
+		{name: "Boolean", status: http.StatusOK, body: `{"done": true}`, path: "$.done", want: 1},
+		{name: "Missing path", status: http.StatusOK, body: `{"data": {}}`, path: "$.data.price", wantErr: true},
+		{name: "Not OK", status: http.StatusServiceUnavailable, body: `{"data": {"price": 1}}`, path: "$.data.price", wantErr: true},
+		{name: "Too large", status: http.StatusOK, body: `{"pad": "` + strings.Repeat("x", maxReading) + `"}`, path: "$.pad", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := standIn(t, tt.status, tt.body)
+			sources := Default(srv.Client(), "")
+
+			got, err := sources.Read(context.Background(), Config{Source: KindHTTP, Target: srv.URL, Path: tt.path})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Read() = %v, want an error", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Read() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublicClientRefusesLocalServer(t *testing.T) {
+	srv := standIn(t, http.StatusOK, `{"price": 1}`)
+	sources := Default(PublicClient(time.Second), "")
+
+	_, err := sources.Read(context.Background(), Config{Source: KindHTTP, Target: srv.URL, Path: "$.price"})
+	if !errors.Is(err, ErrPrivateAddress) {
+		t.Fatalf("Read() error = %v, want ErrPrivateAddress", err)
+	}
+}
+
+func TestPublicClientRefusesRedirectToLocalServer(t *testing.T) {
+	target := standIn(t, http.StatusOK, `{"price": 1}`)
+
+	// The first hop is allowed by a client that only guards the dial, and
+	// the redirect must be refused all the same.
+	redirected := false
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	t.Cleanup(redirect.Close)
+
+	client := PublicClient(time.Second)
+	transport := client.Transport.(*http.Transport)
+	guarded := transport.DialContext
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if addr == redirect.Listener.Addr().String() {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		}
+		return guarded(ctx, network, addr)
+	}
+
+	_, err := Default(client, "").Read(context.Background(), Config{Source: KindHTTP, Target: redirect.URL, Path: "$.price"})
+	if !errors.Is(err, ErrPrivateAddress) {
+		t.Fatalf("Read() error = %v, want ErrPrivateAddress", err)
+	}
+	if !redirected {
+		t.Fatal("the first hop was refused, the redirect was never followed")
+	}
+}
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00:ec2::254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := IsPublic(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("IsPublic(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTPRejectsOtherSchemes(t *testing.T) {
+	for _, target := range []string{"file:///etc/passwd", "ftp://example.com/x", "example.com/price", "http://"} {
+		_, err := HTTP(nil)(target)
+		if !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("HTTP(%q) error = %v, want ErrInvalidURL", target, err)
+		}
+	}
+}
+
+// script installs an executable shell script named name in a temporary
+// directory and returns the directory.
+func script(t *testing.T, name, body string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+body+"\n"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return dir
+}
+
+func TestReadScript(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    float64
+		wantErr string
+	}{
+		{name: "Number", body: `echo '{"price": 42}'`, want: 42},
+		{name: "Failure", body: `echo 'no network' >&2; exit 3`, wantErr: "no network"},
+		{name: "Too much output", body: `yes 1`, wantErr: "printed more than"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sources := Default(nil, script(t, "price.sh", tt.body))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			got, err := sources.Read(ctx, Config{Source: KindScript, Target: "price.sh", Path: "$.price"})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Read() error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Read() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScriptsRejectsOutsideDir(t *testing.T) {
+	dir := script(t, "price.sh", `echo 1`)
+
+	for _, target := range []string{"", "../price.sh", "/bin/sh", ".hidden", "missing.sh"} {
+		_, err := Scripts(dir)(target)
+		if !errors.Is(err, ErrInvalidScript) {
+			t.Errorf("Scripts(%q) error = %v, want ErrInvalidScript", target, err)
+		}
+	}
+}
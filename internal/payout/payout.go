@@ -0,0 +1,236 @@
+// Package payout works out how a resolved market's pool is paid out. It only
+// does arithmetic: callers load the stakes and apply the resulting plan.
+package payout
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"math"
+	"math/big"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// FullShare is the whole pool expressed in basis points.
+const FullShare = 10000
+
+// ErrPoolOverflow is returned when the stakes add up to more coins than an
+// int can count.
+var ErrPoolOverflow = errors.New("payout: the stakes add up to more than a pool can hold")
+
+// Stake is a single bet on the market. Stakes are passed in the order they
+// were placed, which decides rounding ties between otherwise equal bets.
+type Stake struct {
+	BetID     uuid.UUID
+	OutcomeID uuid.UUID
+	Amount    int
+}
+
+// Line is one bet's payout. Base is its share of the outcome pool rounded
+// down and Remainder the leftover coins it was handed on top.
+type Line struct {
+	BetID     uuid.UUID
+	OutcomeID uuid.UUID
+	Amount    int
+	Base      int
+	Remainder int
+}
+
+func (l Line) Payout() int {
+	return l.Base + l.Remainder
+}
+
+// Fees are taken from the pool before anything is paid out. Each is a
+// number of basis points of the pool between 0 and FullShare, rounded down
+// in the bettors' favour.
+type Fees struct {
+	PlatformBps int
+	CreatorBps  int
+}
+
+// OutcomePool is the part of the pool paid to an outcome's bettors.
+type OutcomePool struct {
+	OutcomeID uuid.UUID
+	ShareBps  int
+	Base      int
+	Remainder int
+}
+
+func (o OutcomePool) Total() int {
+	return o.Base + o.Remainder
+}
+
+// Plan is a complete settlement. Lines follow the order of the stakes.
+type Plan struct {
+	Pool        int
+	PlatformFee int
+	CreatorFee  int
+	Outcomes    []OutcomePool
+	Lines       []Line
+}
+
+// Payable is what is left of the pool for bettors once fees are taken.
+func (p Plan) Payable() int {
+	return p.Pool - p.PlatformFee - p.CreatorFee
+}
+
+// Paid is the sum of all payouts. It equals Payable unless no outcome with a
+// share had any bets, in which case nothing is paid out.
+func (p Plan) Paid() int {
+	paid := 0
+	for _, l := range p.Lines {
+		paid += l.Payout()
+	}
+
+	return paid
+}
+
+// Compute settles the stakes against shares, which map outcomes to their part
+// of the pool in basis points. Fees come off the top, then the rest of the
+// pool is split between the outcomes that have bets by their share, and
+// within each outcome pro rata to the stakes. Shares of outcomes nobody bet
+// on are spread over the others.
+func Compute(stakes []Stake, shares map[uuid.UUID]int, fees Fees) (Plan, error) {
+	plan := Plan{Lines: make([]Line, len(stakes))}
+
+	byOutcome := make(map[uuid.UUID][]int)
+	for i, s := range stakes {
+		if s.Amount > math.MaxInt-plan.Pool {
+			return Plan{}, ErrPoolOverflow
+		}
+
+		plan.Pool += s.Amount
+		byOutcome[s.OutcomeID] = append(byOutcome[s.OutcomeID], i)
+		plan.Lines[i] = Line{BetID: s.BetID, OutcomeID: s.OutcomeID, Amount: s.Amount}
+	}
+
+	plan.PlatformFee = feeOf(plan.Pool, fees.PlatformBps)
+	plan.CreatorFee = feeOf(plan.Pool, fees.CreatorBps)
+
+	// Order outcomes by ID so that ties between them do not depend on the
+	// order bets were loaded in.
+	outcomes := make([]uuid.UUID, 0, len(byOutcome))
+	for id := range byOutcome {
+		outcomes = append(outcomes, id)
+	}
+	slices.SortFunc(outcomes, func(a, b uuid.UUID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+
+	weights := make([]int, len(outcomes))
+	for i, id := range outcomes {
+		weights[i] = shares[id]
+	}
+
+	base, extra := Apportion(plan.Payable(), weights)
+
+	for i, id := range outcomes {
+		plan.Outcomes = append(plan.Outcomes, OutcomePool{
+			OutcomeID: id,
+			ShareBps:  weights[i],
+			Base:      base[i],
+			Remainder: extra[i],
+		})
+
+		indexes := byOutcome[id]
+		amounts := make([]int, len(indexes))
+		for j, idx := range indexes {
+			amounts[j] = stakes[idx].Amount
+		}
+
+		lineBase, lineExtra := Apportion(base[i]+extra[i], amounts)
+		for j, idx := range indexes {
+			plan.Lines[idx].Base = lineBase[j]
+			plan.Lines[idx].Remainder = lineExtra[j]
+		}
+	}
+
+	return plan, nil
+}
+
+// feeOf is bps basis points of pool, rounded down. It divides before
+// multiplying so that large pools cannot overflow, which is exact as long
+// as bps is at most FullShare.
+func feeOf(pool, bps int) int {
+	return pool/FullShare*bps + pool%FullShare*bps/FullShare
+}
+
+// Apportion splits total in proportion to weights with the largest remainder
+// method. Every part first gets its exact share rounded down, then the coins
+// left over go one each to the largest fractional remainders. Ties go to the
+// larger weight and then to the earlier one. Zero or negative weights never
+// receive anything, and when no weight is positive nothing is handed out.
+func Apportion(total int, weights []int) (base, extra []int) {
+	base = make([]int, len(weights))
+	extra = make([]int, len(weights))
+
+	sum := new(big.Int)
+	for _, w := range weights {
+		if w > 0 {
+			sum.Add(sum, big.NewInt(int64(w)))
+		}
+	}
+
+	if sum.Sign() == 0 || total <= 0 {
+		return base, extra
+	}
+
+	// The shares are worked out with big integers because total*w, and the
+	// sum of the weights itself, can overflow an int. Each base is at most
+	// total, so it always fits back into one.
+	given := 0
+	remainders := make([]*big.Int, len(weights))
+	order := make([]int, 0, len(weights))
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+
+		product := new(big.Int).Mul(big.NewInt(int64(total)), big.NewInt(int64(w)))
+		quotient, remainder := new(big.Int).QuoRem(product, sum, new(big.Int))
+
+		base[i] = int(quotient.Int64())
+		remainders[i] = remainder
+		given += base[i]
+		order = append(order, i)
+	}
+
+	slices.SortStableFunc(order, func(a, b int) int {
+		if c := remainders[b].Cmp(remainders[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(weights[b], weights[a])
+	})
+
+	// Every remainder is smaller than the sum of the weights, so the
+	// leftover is smaller than the number of positive weights. Clamp it
+	// anyway rather than trust that with a slice bound.
+	for _, i := range order[:min(total-given, len(order))] {
+		extra[i] = 1
+	}
+
+	return base, extra
+}
+
+// ScalarShare is the long side's part of a scalar market's pool, in basis
+// points, when it resolves to value. It grows linearly from nothing at lower
+// to the whole pool at upper, and values outside the range are clamped. On a
+// log scale the position is measured between the logarithms of the bounds,
+// which must then be positive. The short side gets the rest.
+func ScalarShare(value, lower, upper float64, log bool) int {
+	if log {
+		value, lower, upper = math.Log(value), math.Log(lower), math.Log(upper)
+	}
+
+	if math.IsNaN(value) || value <= lower {
+		return 0
+	}
+
+	if value >= upper {
+		return FullShare
+	}
+
+	return int(math.Round((value - lower) / (upper - lower) * FullShare))
+}
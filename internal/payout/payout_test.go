@@ -0,0 +1,369 @@
+package payout
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestApportion(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		weights   []int
+		wantBase  []int
+		wantExtra []int
+	}{
+		{
+			name:      "Exact split",
+			total:     60,
+			weights:   []int{1, 2, 3},
+			wantBase:  []int{10, 20, 30},
+			wantExtra: []int{0, 0, 0},
+		},
+		{
+			name:      "Equal weights tie by order",
+			total:     10,
+			weights:   []int{1, 1, 1},
+			wantBase:  []int{3, 3, 3},
+			wantExtra: []int{1, 0, 0},
+		},
+		{
+			name:      "Equal remainders tie by weight",
+			total:     2,
+			weights:   []int{1, 3},
+			wantBase:  []int{0, 1},
+			wantExtra: []int{0, 1},
+		},
+		{
+			name:      "Largest remainder first",
+			total:     10,
+			weights:   []int{1, 2},
+			wantBase:  []int{3, 6},
+			wantExtra: []int{0, 1},
+		},
+		{
+			name:      "Zero weight gets nothing",
+			total:     10,
+			weights:   []int{0, 3, 0},
+			wantBase:  []int{0, 10, 0},
+			wantExtra: []int{0, 0, 0},
+		},
+		{
+			name:      "Large total",
+			total:     math.MaxInt,
+			weights:   []int{1, 2},
+			wantBase:  []int{math.MaxInt / 3, math.MaxInt / 3 * 2},
+			wantExtra: []int{0, 1},
+		},
+		{
+			name:      "Large weights",
+			total:     math.MaxInt,
+			weights:   []int{math.MaxInt, math.MaxInt},
+			wantBase:  []int{math.MaxInt / 2, math.MaxInt / 2},
+			wantExtra: []int{1, 0},
+		},
+		{
+			name:      "All zero weights",
+			total:     10,
+			weights:   []int{0, 0},
+			wantBase:  []int{0, 0},
+			wantExtra: []int{0, 0},
+		},
+		{
+			name:      "Nothing to hand out",
+			total:     0,
+			weights:   []int{1, 2},
+			wantBase:  []int{0, 0},
+			wantExtra: []int{0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, extra := Apportion(tt.total, tt.weights)
+
+			if !slices.Equal(base, tt.wantBase) {
+				t.Errorf("base = %v, want %v", base, tt.wantBase)
+			}
+			if !slices.Equal(extra, tt.wantExtra) {
+				t.Errorf("extra = %v, want %v", extra, tt.wantExtra)
+			}
+		})
+	}
+}
+
+func TestApportionLeftover(t *testing.T) {
+	weightSets := [][]int{
+		{1},
+		{1, 1},
+		{3, 0, 7},
+		{1, 1, 1, 1, 1, 1, 1},
+		{9999, 1},
+		{100, 250, 333, 0, 17},
+	}
+
+	for _, weights := range weightSets {
+		nonZero := 0
+		for _, w := range weights {
+			if w > 0 {
+				nonZero++
+			}
+		}
+
+		for total := 0; total <= 1000; total += 7 {
+			base, extra := Apportion(total, weights)
+
+			given, leftover := 0, 0
+			for i := range weights {
+				if extra[i] < 0 || extra[i] > 1 {
+					t.Fatalf("Apportion(%d, %v): extra[%d] = %d", total, weights, i, extra[i])
+				}
+				if weights[i] == 0 && base[i]+extra[i] != 0 {
+					t.Fatalf("Apportion(%d, %v): zero weight %d got %d", total, weights, i, base[i]+extra[i])
+				}
+				given += base[i] + extra[i]
+				leftover += extra[i]
+			}
+
+			if given != total {
+				t.Fatalf("Apportion(%d, %v) handed out %d", total, weights, given)
+			}
+			if leftover > nonZero {
+				t.Fatalf("Apportion(%d, %v): leftover %d exceeds %d non-zero weights", total, weights, leftover, nonZero)
+			}
+		}
+	}
+}
+
+// ids returns n outcome or bet IDs in ascending order, which is the order
+// Compute visits outcomes in.
+func ids(n int) []uuid.UUID {
+	out := make([]uuid.UUID, n)
+	for i := range out {
+		out[i][15] = byte(i + 1)
+	}
+	return out
+}
+
+func TestCompute(t *testing.T) {
+	o := ids(3)
+	yes, no, other := o[0], o[1], o[2]
+	b := ids(4)
+
+	tests := []struct {
+		name         string
+		stakes       []Stake
+		shares       map[uuid.UUID]int
+		fees         Fees
+		wantPlatform int
+		wantCreator  int
+		wantLines    []int
+	}{
+		{
+			name: "Winner takes the pool",
+			stakes: []Stake{
+				{BetID: b[0], OutcomeID: yes, Amount: 100},
+				{BetID: b[1], OutcomeID: no, Amount: 300},
+				{BetID: b[2], OutcomeID: yes, Amount: 300},
+			},
+			shares:    map[uuid.UUID]int{yes: FullShare},
+			wantLines: []int{175, 0, 525},
+		},
+		{
+			name: "Fees come off the top",
+			stakes: []Stake{
+				{BetID: b[0], OutcomeID: yes, Amount: 1000},
+				{BetID: b[1], OutcomeID: no, Amount: 1000},
+			},
+			shares:       map[uuid.UUID]int{yes: FullShare},
+			fees:         Fees{PlatformBps: 200, CreatorBps: 100},
+			wantPlatform: 40,
+			wantCreator:  20,
+			wantLines:    []int{1940, 0},
+		},
+		{
+			name: "Split shares",
+			stakes: []Stake{
+				{BetID: b[0], OutcomeID: yes, Amount: 100},
+				{BetID: b[1], OutcomeID: no, Amount: 300},
+			},
+			shares:    map[uuid.UUID]int{yes: 2500, no: 7500},
+			wantLines: []int{100, 300},
+		},
+		{
+			name: "Share of an outcome without bets goes to the others",
+			stakes: []Stake{
+				{BetID: b[0], OutcomeID: yes, Amount: 100},
+				{BetID: b[1], OutcomeID: no, Amount: 300},
+			},
+			shares:    map[uuid.UUID]int{yes: 5000, other: 5000},
+			wantLines: []int{400, 0},
+		},
+		{
+			name: "Nothing paid when no winning outcome has bets",
+			stakes: []Stake{
+				{BetID: b[0], OutcomeID: yes, Amount: 100},
+				{BetID: b[1], OutcomeID: no, Amount: 300},
+			},
+			shares:    map[uuid.UUID]int{other: FullShare},
+			wantLines: []int{0, 0},
+		},
+		{
+			// Liquidity is passed before bets, so it wins rounding ties
+			// against a bet of the same size.
+			name: "Liquidity lines are paid before bets",
+			stakes: []Stake{
+				{BetID: b[0], OutcomeID: yes, Amount: 100},
+				{BetID: b[1], OutcomeID: yes, Amount: 100},
+				{BetID: b[2], OutcomeID: no, Amount: 101},
+			},
+			shares:    map[uuid.UUID]int{yes: FullShare},
+			wantLines: []int{151, 150, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := Compute(tt.stakes, tt.shares, tt.fees)
+			if err != nil {
+				t.Fatalf("Compute() error = %v", err)
+			}
+
+			if plan.PlatformFee != tt.wantPlatform {
+				t.Errorf("PlatformFee = %d, want %d", plan.PlatformFee, tt.wantPlatform)
+			}
+			if plan.CreatorFee != tt.wantCreator {
+				t.Errorf("CreatorFee = %d, want %d", plan.CreatorFee, tt.wantCreator)
+			}
+
+			lines := make([]int, len(plan.Lines))
+			for i, l := range plan.Lines {
+				if l.BetID != tt.stakes[i].BetID {
+					t.Errorf("line %d is for bet %s, want %s", i, l.BetID, tt.stakes[i].BetID)
+				}
+				lines[i] = l.Payout()
+			}
+			if !slices.Equal(lines, tt.wantLines) {
+				t.Errorf("payouts = %v, want %v", lines, tt.wantLines)
+			}
+		})
+	}
+}
+
+func TestComputeSumsToPool(t *testing.T) {
+	o := ids(3)
+	b := ids(6)
+
+	stakes := []Stake{
+		{BetID: b[0], OutcomeID: o[0], Amount: 137},
+		{BetID: b[1], OutcomeID: o[1], Amount: 211},
+		{BetID: b[2], OutcomeID: o[0], Amount: 59},
+		{BetID: b[3], OutcomeID: o[2], Amount: 1009},
+		{BetID: b[4], OutcomeID: o[1], Amount: 3},
+		{BetID: b[5], OutcomeID: o[2], Amount: 500},
+	}
+
+	shareSets := []map[uuid.UUID]int{
+		{o[0]: FullShare},
+		{o[0]: 3333, o[1]: 3333, o[2]: 3334},
+		{o[1]: 1, o[2]: 9999},
+		{o[0]: 7000, o[2]: 3000},
+	}
+
+	feeSets := []Fees{{}, {PlatformBps: 200, CreatorBps: 100}, {PlatformBps: 1000, CreatorBps: 1000}}
+
+	for _, shares := range shareSets {
+		for _, fees := range feeSets {
+			plan, err := Compute(stakes, shares, fees)
+			if err != nil {
+				t.Fatalf("Compute() error = %v", err)
+			}
+
+			if plan.Pool != 1919 {
+				t.Fatalf("Pool = %d, want 1919", plan.Pool)
+			}
+
+			if got := plan.Paid() + plan.PlatformFee + plan.CreatorFee; got != plan.Pool {
+				t.Errorf("shares %v, fees %v: paid %d plus fees %d and %d is %d, want %d",
+					shares, fees, plan.Paid(), plan.PlatformFee, plan.CreatorFee, got, plan.Pool)
+			}
+
+			outcomes := 0
+			for _, op := range plan.Outcomes {
+				outcomes += op.Total()
+			}
+			if outcomes != plan.Payable() {
+				t.Errorf("shares %v, fees %v: outcome pools sum to %d, want %d", shares, fees, outcomes, plan.Payable())
+			}
+		}
+	}
+}
+
+func TestComputeLargeAmounts(t *testing.T) {
+	o := ids(2)
+	b := ids(3)
+
+	stakes := []Stake{
+		{BetID: b[0], OutcomeID: o[0], Amount: math.MaxInt / 2},
+		{BetID: b[1], OutcomeID: o[1], Amount: math.MaxInt / 2},
+	}
+	fees := Fees{PlatformBps: 1000, CreatorBps: 1000}
+
+	plan, err := Compute(stakes, map[uuid.UUID]int{o[0]: 3333, o[1]: 6667}, fees)
+	if err != nil {
+		t.Fatalf("Compute() error = %v", err)
+	}
+
+	if want := (math.MaxInt - 1) / 10; plan.PlatformFee != want || plan.CreatorFee != want {
+		t.Errorf("fees = %d and %d, want %d", plan.PlatformFee, plan.CreatorFee, want)
+	}
+	if got := plan.Paid() + plan.PlatformFee + plan.CreatorFee; got != plan.Pool {
+		t.Errorf("paid %d plus fees is %d, want %d", plan.Paid(), got, plan.Pool)
+	}
+
+	stakes = append(stakes, Stake{BetID: b[2], OutcomeID: o[0], Amount: 2})
+	_, err = Compute(stakes, map[uuid.UUID]int{o[0]: FullShare}, fees)
+	if !errors.Is(err, ErrPoolOverflow) {
+		t.Errorf("Compute() error = %v, want ErrPoolOverflow", err)
+	}
+}
+
+func TestScalarShare(t *testing.T) {
+	tests := []struct {
+		name  string
+		value float64
+		lower float64
+		upper float64
+		log   bool
+		want  int
+	}{
+		{name: "Lower bound", value: 0, lower: 0, upper: 100, want: 0},
+		{name: "Upper bound", value: 100, lower: 0, upper: 100, want: FullShare},
+		{name: "Below range", value: -50, lower: 0, upper: 100, want: 0},
+		{name: "Above range", value: 150, lower: 0, upper: 100, want: FullShare},
+		{name: "Midpoint", value: 50, lower: 0, upper: 100, want: 5000},
+		{name: "Rounded", value: 1, lower: 0, upper: 3, want: 3333},
+		{name: "Log midpoint", value: 10, lower: 1, upper: 100, log: true, want: 5000},
+		{name: "Log quarter", value: 10, lower: 1, upper: 10000, log: true, want: 2500},
+		{name: "Log below range", value: 0.5, lower: 1, upper: 100, log: true, want: 0},
+		{name: "Log of zero", value: 0, lower: 1, upper: 100, log: true, want: 0},
+		{name: "Log of negative", value: -10, lower: 1, upper: 100, log: true, want: 0},
+		{name: "NaN", value: math.NaN(), lower: 0, upper: 100, want: 0},
+		{name: "Positive infinity", value: math.Inf(1), lower: 0, upper: 100, want: FullShare},
+		{name: "Negative infinity", value: math.Inf(-1), lower: 0, upper: 100, want: 0},
+		{name: "Log of infinity", value: math.Inf(1), lower: 1, upper: 100, log: true, want: FullShare},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ScalarShare(tt.value, tt.lower, tt.upper, tt.log)
+			if got != tt.want {
+				t.Errorf("ScalarShare(%v, %v, %v, %v) = %d, want %d", tt.value, tt.lower, tt.upper, tt.log, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+// Package recurrence parses the rules recurring markets repeat on and works
+// out when they next occur. A rule is either a shorthand such as
+// "daily 18:00", "weekly fri 18:00" or "monthly 1 09:00", or a five field
+// cron expression such as "0 18 * * 5".
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRule = errors.New("the rule must look like \"daily 18:00\", \"weekly fri 18:00\", \"monthly 1 09:00\" or a cron expression such as \"0 18 * * 5\"")
+var ErrNeverOccurs = errors.New("the rule never occurs")
+var ErrTooFrequent = errors.New("the rule must not occur more than once an hour")
+
+// searchDays bounds how far ahead Next looks, so rules that can never occur,
+// like the 31st of February, give up instead of looping forever.
+const searchDays = 5 * 366
+
+var weekdays = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// Rule is a parsed recurrence, held as the cron fields it allows.
+type Rule struct {
+	text     string
+	minutes  []int
+	hours    []int
+	days     []int
+	months   []int
+	weekdays []int
+
+	// As in cron, when both the day of the month and the weekday are
+	// restricted a time matching either one occurs.
+	anyDay     bool
+	anyWeekday bool
+}
+
+// Parse reads a rule in any of the accepted forms.
+func Parse(text string) (Rule, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	fields := strings.Fields(text)
+
+	var cron []string
+	switch {
+	case len(fields) == 2 && fields[0] == "daily":
+		cron = clock(fields[1], "*", "*")
+	case len(fields) == 3 && fields[0] == "weekly":
+		cron = clock(fields[2], "*", fields[1])
+	case len(fields) == 3 && fields[0] == "monthly":
+		cron = clock(fields[2], fields[1], "*")
+	case len(fields) == 5:
+		cron = fields
+	}
+
+	if cron == nil {
+		return Rule{}, ErrInvalidRule
+	}
+
+	r := Rule{text: strings.Join(fields, " ")}
+
+	var err error
+	if r.minutes, err = parseField(cron[0], 0, 59, nil); err != nil {
+		return Rule{}, err
+	}
+	if r.hours, err = parseField(cron[1], 0, 23, nil); err != nil {
+		return Rule{}, err
+	}
+	if r.days, err = parseField(cron[2], 1, 31, nil); err != nil {
+		return Rule{}, err
+	}
+	if r.months, err = parseField(cron[3], 1, 12, nil); err != nil {
+		return Rule{}, err
+	}
+	if r.weekdays, err = parseField(cron[4], 0, 7, weekdays); err != nil {
+		return Rule{}, err
+	}
+
+	// Each occurrence is a new market, so a single minute of the hour keeps
+	// them at least an hour apart.
+	if len(r.minutes) > 1 {
+		return Rule{}, ErrTooFrequent
+	}
+
+	// Cron accepts 7 as well as 0 for Sunday.
+	for i, d := range r.weekdays {
+		if d == 7 {
+			r.weekdays[i] = 0
+		}
+	}
+
+	r.anyDay = cron[2] == "*"
+	r.anyWeekday = cron[4] == "*"
+
+	// Starting on a leap day lets rules for the 29th of February through.
+	if r.Next(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return Rule{}, ErrNeverOccurs
+	}
+
+	return r, nil
+}
+
+// clock turns "18:00" into the minute and hour fields of a cron expression.
+// A malformed time yields fields that fail to parse.
+func clock(hhmm, day, weekday string) []string {
+	hour, minute, ok := strings.Cut(hhmm, ":")
+	if !ok {
+		return []string{"x", "x", day, "*", weekday}
+	}
+
+	return []string{minute, hour, day, "*", weekday}
+}
+
+// parseField reads one cron field: "*", a number, a range "1-5", a step
+// "*/15" or "1-31/2", or a comma separated list of those. names maps words
+// such as weekday abbreviations to numbers.
+func parseField(field string, min, max int, names map[string]int) ([]int, error) {
+	var values []int
+
+	for _, part := range strings.Split(field, ",") {
+		span, stepText, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n < 1 {
+				return nil, ErrInvalidRule
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		if span != "*" {
+			from, to, isRange := strings.Cut(span, "-")
+
+			var err error
+			if lo, err = value(from, names); err != nil {
+				return nil, err
+			}
+
+			hi = lo
+			if isRange {
+				if hi, err = value(to, names); err != nil {
+					return nil, err
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return nil, ErrInvalidRule
+		}
+
+		for v := lo; v <= hi; v += step {
+			values = append(values, v)
+		}
+	}
+
+	slices.Sort(values)
+	return slices.Compact(values), nil
+}
+
+func value(text string, names map[string]int) (int, error) {
+	if n, ok := names[text]; ok {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, ErrInvalidRule
+	}
+
+	return n, nil
+}
+
+// Next returns the first time after after that the rule occurs, in after's
+// location. Times skipped by a daylight saving change are moved forward as
+// time.Date does. It returns the zero time when the rule never occurs.
+func (r Rule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	day := time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, loc)
+
+	for range searchDays {
+		if r.matchesDay(day) {
+			for _, h := range r.hours {
+				for _, m := range r.minutes {
+					t := time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, loc)
+					if t.After(after) {
+						return t
+					}
+				}
+			}
+		}
+
+		day = day.AddDate(0, 0, 1)
+	}
+
+	return time.Time{}
+}
+
+func (r Rule) matchesDay(day time.Time) bool {
+	if !slices.Contains(r.months, int(day.Month())) {
+		return false
+	}
+
+	dayOK := slices.Contains(r.days, day.Day())
+	weekdayOK := slices.Contains(r.weekdays, int(day.Weekday()))
+
+	switch {
+	case r.anyDay && r.anyWeekday:
+		return true
+	case r.anyDay:
+		return weekdayOK
+	case r.anyWeekday:
+		return dayOK
+	}
+
+	return dayOK || weekdayOK
+}
+
+// String returns the rule as it was written, in lower case.
+func (r Rule) String() string {
+	return r.text
+}
+
+// Describe explains shorthand rules for a single time in words and falls
+// back to the rule itself for anything else.
+func (r Rule) Describe() string {
+	fields := strings.Fields(r.text)
+	if len(fields) == 5 || len(r.hours) != 1 || len(r.minutes) != 1 {
+		return r.text
+	}
+
+	at := fmt.Sprintf("%02d:%02d", r.hours[0], r.minutes[0])
+
+	switch {
+	case fields[0] == "daily":
+		return "every day at " + at
+	case fields[0] == "weekly" && len(r.weekdays) == 1:
+		return fmt.Sprintf("every %s at %s", time.Weekday(r.weekdays[0]), at)
+	case fields[0] == "monthly" && len(r.days) == 1:
+		return fmt.Sprintf("on day %d of every month at %s", r.days[0], at)
+	}
+
+	return r.text
+}
@@ -0,0 +1,189 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr error
+	}{
+		{name: "Daily", text: "daily 18:00"},
+		{name: "Weekly", text: "weekly fri 18:00"},
+		{name: "Monthly", text: "monthly 1 09:00"},
+		{name: "Upper case and spaces", text: "  Weekly  FRI 18:00 "},
+		{name: "Cron", text: "0 18 * * 5"},
+		{name: "Ranges and steps", text: "30 9-17/4 1-15/2 * mon-fri"},
+		{name: "Lists", text: "0 9,18 1,15 1,7 *"},
+		{name: "Sunday as 7", text: "0 12 * * 7"},
+		{name: "Leap day", text: "0 0 29 2 *"},
+		{name: "Empty", text: "", wantErr: ErrInvalidRule},
+		{name: "Unknown shorthand", text: "hourly 18:00", wantErr: ErrInvalidRule},
+		{name: "Time without colon", text: "daily 1800", wantErr: ErrInvalidRule},
+		{name: "Hour out of range", text: "daily 24:00", wantErr: ErrInvalidRule},
+		{name: "Unknown weekday", text: "weekly fry 18:00", wantErr: ErrInvalidRule},
+		{name: "Weekday out of range", text: "0 18 * * 8", wantErr: ErrInvalidRule},
+		{name: "Backwards range", text: "0 18 20-10 * *", wantErr: ErrInvalidRule},
+		{name: "Zero step", text: "0 */0 * * *", wantErr: ErrInvalidRule},
+		{name: "Four fields", text: "0 18 * *", wantErr: ErrInvalidRule},
+		{name: "Every minute", text: "* * * * *", wantErr: ErrTooFrequent},
+		{name: "Twice an hour", text: "0,30 9 * * *", wantErr: ErrTooFrequent},
+		{name: "Minute step", text: "*/15 9 * * *", wantErr: ErrTooFrequent},
+		{name: "30th of February", text: "0 0 30 2 *", wantErr: ErrNeverOccurs},
+		{name: "31st of April", text: "0 0 31 4 *", wantErr: ErrNeverOccurs},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.text)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse(%q) error = %v, want %v", tt.text, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	utc := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+	}
+	local := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, madrid)
+	}
+
+	tests := []struct {
+		name  string
+		rule  string
+		after time.Time
+		want  time.Time
+	}{
+		{
+			name:  "Daily later today",
+			rule:  "daily 18:00",
+			after: utc(2025, time.March, 10, 12, 0),
+			want:  utc(2025, time.March, 10, 18, 0),
+		},
+		{
+			name:  "Daily strictly after",
+			rule:  "daily 18:00",
+			after: utc(2025, time.March, 10, 18, 0),
+			want:  utc(2025, time.March, 11, 18, 0),
+		},
+		{
+			name:  "Weekly",
+			rule:  "weekly fri 18:00",
+			after: utc(2025, time.March, 10, 12, 0),
+			want:  utc(2025, time.March, 14, 18, 0),
+		},
+		{
+			name:  "Sunday as 7",
+			rule:  "0 12 * * 7",
+			after: utc(2025, time.March, 10, 12, 0),
+			want:  utc(2025, time.March, 16, 12, 0),
+		},
+		{
+			name:  "Monthly",
+			rule:  "monthly 1 09:00",
+			after: utc(2025, time.March, 10, 12, 0),
+			want:  utc(2025, time.April, 1, 9, 0),
+		},
+		{
+			name:  "Hour range with a step",
+			rule:  "15 9-17/4 * * *",
+			after: utc(2025, time.March, 10, 10, 0),
+			want:  utc(2025, time.March, 10, 13, 15),
+		},
+		{
+			name:  "Weekday range skips the weekend",
+			rule:  "0 9 * * mon-fri",
+			after: utc(2025, time.March, 14, 10, 0),
+			want:  utc(2025, time.March, 17, 9, 0),
+		},
+		{
+			name:  "Day list",
+			rule:  "0 9 1,15 * *",
+			after: utc(2025, time.March, 10, 12, 0),
+			want:  utc(2025, time.March, 15, 9, 0),
+		},
+		{
+			name:  "Day of the month or weekday, day first",
+			rule:  "0 9 13 * mon",
+			after: utc(2025, time.March, 10, 10, 0),
+			want:  utc(2025, time.March, 13, 9, 0),
+		},
+		{
+			name:  "Day of the month or weekday, weekday first",
+			rule:  "0 9 13 * mon",
+			after: utc(2025, time.March, 13, 10, 0),
+			want:  utc(2025, time.March, 17, 9, 0),
+		},
+		{
+			name:  "Weekday within a month",
+			rule:  "0 9 * 6 mon",
+			after: utc(2025, time.March, 10, 12, 0),
+			want:  utc(2025, time.June, 2, 9, 0),
+		},
+		{
+			name:  "29th of February waits for a leap year",
+			rule:  "0 0 29 2 *",
+			after: utc(2025, time.March, 1, 0, 0),
+			want:  utc(2028, time.February, 29, 0, 0),
+		},
+		{
+			name:  "31st skips shorter months",
+			rule:  "monthly 31 12:00",
+			after: utc(2025, time.April, 1, 0, 0),
+			want:  utc(2025, time.May, 31, 12, 0),
+		},
+		{
+			name:  "31st rolls over the end of the year",
+			rule:  "monthly 31 12:00",
+			after: utc(2025, time.December, 31, 13, 0),
+			want:  utc(2026, time.January, 31, 12, 0),
+		},
+		{
+			name:  "Time skipped by the spring change moves forward",
+			rule:  "daily 02:30",
+			after: local(2025, time.March, 30, 0, 0),
+			want:  local(2025, time.March, 30, 3, 30),
+		},
+		{
+			name:  "Wall clock kept across the spring change",
+			rule:  "daily 18:00",
+			after: local(2025, time.March, 29, 18, 0),
+			want:  local(2025, time.March, 30, 18, 0),
+		},
+		{
+			name:  "Wall clock kept across the autumn change",
+			rule:  "daily 18:00",
+			after: local(2025, time.October, 25, 18, 0),
+			want:  local(2025, time.October, 26, 18, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.rule, err)
+			}
+
+			got := r.Next(tt.after)
+			if !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.after, got, tt.want)
+			}
+			if got.Location() != tt.after.Location() {
+				t.Errorf("Next(%v) is in %v, want %v", tt.after, got.Location(), tt.after.Location())
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"foresee/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AchievementRule describes a badge. Earners inspects an event and returns
+// the users that qualify; rules that do not care about the event return
+// nil. Awarding is idempotent so rules do not need to remember who already
+// has the badge.
+//
+// Holds is set for badges a corrected resolution can take away. It reports
+// whether the user still qualifies, see AchievementService.recheck.
+type AchievementRule struct {
+	Code        string
+	Name        string
+	Icon        string
+	Description string
+	Bonus       int
+	Earners     func(s *AchievementService, ev Event) ([]uuid.UUID, error)
+	Holds       func(s *AchievementService, userID uuid.UUID) (bool, error)
+}
+
+type Achievement struct {
+	AchievementRule
+	Paid      int
+	AwardedAt time.Time
+}
+
+const upsetThreshold = 0.10
+
+var AchievementRules = []AchievementRule{
+	{
+		Code:        "first_bet",
+		Name:        "First bet",
+		Icon:        "🎯",
+		Description: "Placed a first bet.",
+		Bonus:       100,
+		Earners: func(s *AchievementService, ev Event) ([]uuid.UUID, error) {
+			if e, ok := ev.(BetPlaced); ok {
+				return []uuid.UUID{e.UserID}, nil
+			}
+			return nil, nil
+		},
+	},
+	{
+		Code:        "first_win",
+		Name:        "First win",
+		Icon:        "🏆",
+		Description: "Won a bet for the first time.",
+		Bonus:       100,
+		Earners: func(s *AchievementService, ev Event) ([]uuid.UUID, error) {
+			var users []uuid.UUID
+			for _, b := range wonBets(ev) {
+				users = append(users, b.UserID)
+			}
+			return users, nil
+		},
+		Holds: func(s *AchievementService, userID uuid.UUID) (bool, error) {
+			return s.Achievements.HasWin(userID, nil)
+		},
+	},
+	{
+		Code:        "upset",
+		Name:        "Called the upset",
+		Icon:        "🔮",
+		Description: "Won a bet placed at an implied probability under 10%.",
+		Bonus:       500,
+		Earners: func(s *AchievementService, ev Event) ([]uuid.UUID, error) {
+			var users []uuid.UUID
+			for _, b := range wonBets(ev) {
+				if b.ImpliedProbability != nil && *b.ImpliedProbability < upsetThreshold {
+					users = append(users, b.UserID)
+				}
+			}
+			return users, nil
+		},
+		Holds: func(s *AchievementService, userID uuid.UUID) (bool, error) {
+			threshold := upsetThreshold
+			return s.Achievements.HasWin(userID, &threshold)
+		},
+	},
+	{
+		Code:        "resolver_10",
+		Name:        "Trusted resolver",
+		Icon:        "⚖️",
+		Description: "Resolved 10 markets.",
+		Bonus:       250,
+		Earners: func(s *AchievementService, ev Event) ([]uuid.UUID, error) {
+			e, ok := ev.(MarketResolved)
+			if !ok || e.ResolvedBy == nil {
+				return nil, nil
+			}
+
+			count, err := s.Markets.CountResolvedBy(*e.ResolvedBy)
+			if err != nil || count < 10 {
+				return nil, err
+			}
+			return []uuid.UUID{*e.ResolvedBy}, nil
+		},
+	},
+	{
+		Code:        "streak_30",
+		Name:        "Regular",
+		Icon:        "🔥",
+		Description: "Claimed the daily reward 30 days in a row.",
+		Bonus:       1000,
+		Earners: func(s *AchievementService, ev Event) ([]uuid.UUID, error) {
+			if e, ok := ev.(DailyRewardClaimed); ok && e.Streak >= 30 {
+				return []uuid.UUID{e.UserID}, nil
+			}
+			return nil, nil
+		},
+	},
+}
+
+// wonBets returns the bets on the outcome a market resolved or was
+// corrected to. A payout alone is no win, as split and scalar markets pay
+// part of the pool to the outcomes that lost.
+func wonBets(ev Event) []SettledBet {
+	var outcomeID uuid.UUID
+	var bets []SettledBet
+
+	switch e := ev.(type) {
+	case MarketResolved:
+		outcomeID, bets = e.OutcomeID, e.Bets
+	case ResolutionCorrected:
+		outcomeID, bets = e.OutcomeID, e.Bets
+	}
+
+	var won []SettledBet
+	for _, b := range bets {
+		if b.OutcomeID == outcomeID {
+			won = append(won, b)
+		}
+	}
+
+	return won
+}
+
+type AchievementService struct {
+	Achievements *models.AchievementModel
+	Markets      *models.MarketModel
+	UserService  *UserService
+}
+
+// Handle evaluates every rule against the event and awards what is due.
+// Bonuses are credited in the same transaction as the award so a badge can
+// never pay out twice.
+func (s *AchievementService) Handle(ev Event) error {
+	for _, rule := range AchievementRules {
+		users, err := rule.Earners(s, ev)
+		if err != nil {
+			return err
+		}
+
+		for _, userID := range users {
+			err = s.award(rule, userID)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if e, ok := ev.(ResolutionCorrected); ok {
+		return s.recheck(e)
+	}
+
+	return nil
+}
+
+// recheck revokes the badges the bettors of a corrected market no longer
+// qualify for, once those the correction earned have been awarded.
+func (s *AchievementService) recheck(e ResolutionCorrected) error {
+	seen := make(map[uuid.UUID]bool)
+
+	for _, b := range e.Bets {
+		if seen[b.UserID] {
+			continue
+		}
+		seen[b.UserID] = true
+
+		for _, rule := range AchievementRules {
+			if rule.Holds == nil {
+				continue
+			}
+
+			holds, err := rule.Holds(s, b.UserID)
+			if err != nil {
+				return err
+			}
+
+			if !holds {
+				err = s.revoke(rule, b.UserID)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *AchievementService) award(rule AchievementRule, userID uuid.UUID) error {
+	tx, err := s.Achievements.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	awarded, err := s.Achievements.Award(tx, userID, rule.Code, rule.Bonus)
+	if err != nil {
+		return err
+	}
+
+	if awarded && rule.Bonus > 0 {
+		err = s.UserService.IncreaseBalanceBy(tx, userID, rule.Bonus)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// revoke takes back the badge and the bonus paid for it. As with corrected
+// payouts, the balance may go negative.
+func (s *AchievementService) revoke(rule AchievementRule, userID uuid.UUID) error {
+	tx, err := s.Achievements.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bonus, revoked, err := s.Achievements.Revoke(tx, userID, rule.Code)
+	if err != nil {
+		return err
+	}
+
+	if revoked && bonus > 0 {
+		err = s.UserService.DecreaseBalanceBy(tx, userID, bonus)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *AchievementService) ForUser(userID uuid.UUID) ([]Achievement, error) {
+	rows, err := s.Achievements.ForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var achievements []Achievement
+	for _, row := range rows {
+		for _, rule := range AchievementRules {
+			if rule.Code == row.Code {
+				achievements = append(achievements, Achievement{
+					AchievementRule: rule,
+					Paid:            row.Bonus,
+					AwardedAt:       row.AwardedAt,
+				})
+				break
+			}
+		}
+	}
+
+	return achievements, nil
+}
@@ -14,12 +14,15 @@ type BetService struct {
 	UserService   *UserService
 	MarketService *MarketService
 	Outcome       *models.OutcomeModel
+	Seasons       *models.SeasonModel
+	Events        *Events
 }
 
 var ErrInsufficientBalance = errors.New("you cannot place a bet that is higher than your current balance")
 var ErrMarketExpired = errors.New("you cannot place a bet in an expired market")
 var ErrMarketNotOpen = errors.New("you cannot place a bet in a market that is not open")
 var ErrOutcomeNotFound = errors.New("the selected outcome does not exist in the selected market")
+var ErrNotGroupMember = errors.New("this market is only open to members of its group")
 
 func (s BetService) Place(userID uuid.UUID, marketID uuid.UUID, outcomeID uuid.UUID, amount int) error {
 	tx, err := s.Bets.DB.Begin()
@@ -34,15 +37,35 @@ func (s BetService) Place(userID uuid.UUID, marketID uuid.UUID, outcomeID uuid.U
 		return err
 	}
 
+	if user.SuspendedAt != nil {
+		return ErrUserSuspended
+	}
+
 	if user.Balance < amount {
 		return ErrInsufficientBalance
 	}
 
+	// Holding a share lock keeps the creator from editing the terms while
+	// the first bet is being placed.
+	err = s.MarketService.Markets.LockForShare(tx, marketID)
+	if err != nil {
+		return err
+	}
+
 	market, err := s.MarketService.Get(marketID)
 	if err != nil {
 		return err
 	}
 
+	allowed, err := s.MarketService.CanAccess(market, userID)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return ErrNotGroupMember
+	}
+
 	if time.Now().After(market.ExpiresAt) {
 		return ErrMarketExpired
 	}
@@ -51,6 +74,11 @@ func (s BetService) Place(userID uuid.UUID, marketID uuid.UUID, outcomeID uuid.U
 		return ErrMarketNotOpen
 	}
 
+	flagged, err := s.MarketService.checkResolverBet(market, userID)
+	if err != nil {
+		return err
+	}
+
 	outcome, err := s.Outcome.SelectForUpdate(tx, outcomeID)
 	if err != nil {
 		return err
@@ -60,7 +88,23 @@ func (s BetService) Place(userID uuid.UUID, marketID uuid.UUID, outcomeID uuid.U
 		return ErrOutcomeNotFound
 	}
 
-	err = s.Bets.Place(tx, userID, marketID, outcomeID, amount)
+	totalPool := 0
+	for _, o := range market.Outcomes {
+		if o.ID == outcomeID {
+			totalPool += outcome.PoolAmount
+			continue
+		}
+		totalPool += o.PoolAmount
+	}
+
+	seasonID, err := s.Seasons.ActiveID(tx)
+	if err != nil {
+		return err
+	}
+
+	impliedProbability := ImpliedProbability(outcome.PoolAmount, totalPool, amount)
+
+	err = s.Bets.Place(tx, userID, marketID, outcomeID, amount, impliedProbability, seasonID, flagged)
 	if err != nil {
 		return err
 	}
@@ -77,7 +121,31 @@ func (s BetService) Place(userID uuid.UUID, marketID uuid.UUID, outcomeID uuid.U
 		return err
 	}
 
-	return tx.Commit()
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	s.Events.Publish(BetPlaced{
+		UserID:             userID,
+		MarketID:           marketID,
+		OutcomeID:          outcomeID,
+		Amount:             amount,
+		ImpliedProbability: impliedProbability,
+	})
+
+	return nil
+}
+
+// ImpliedProbability is the share of the pool backing the outcome once the
+// stake itself has been added, which is what the bettor is effectively paid
+// against.
+func ImpliedProbability(outcomePool, totalPool, amount int) float64 {
+	if totalPool+amount == 0 {
+		return 0
+	}
+
+	return float64(outcomePool+amount) / float64(totalPool+amount)
 }
 
 func (s BetService) GetUserBetHistory(userID uuid.UUID) ([]models.BetHistoryRow, error) {
@@ -91,3 +159,9 @@ func (s BetService) ForMarketForUpdate(tx *sql.Tx, marketID uuid.UUID) ([]models
 func (s BetService) SetPayout(tx *sql.Tx, betID uuid.UUID, payout int) error {
 	return s.Bets.SetPayout(tx, betID, payout)
 }
+
+// Flagged lists the bets the market's resolver placed under
+// models.IntegrityFlag.
+func (s BetService) Flagged(marketID uuid.UUID) ([]models.Bet, error) {
+	return s.Bets.Flagged(marketID)
+}
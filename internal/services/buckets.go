@@ -0,0 +1,179 @@
+package services
+
+import (
+	"errors"
+	"foresee/internal/models"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrBucketRange = errors.New("the range must end after it starts and split into at most 50 buckets")
+var ErrBucketDate = errors.New("the date must be valid")
+
+// MaxBuckets limits how many buckets a range is split into, not counting the
+// open ended buckets before and after it.
+const MaxBuckets = 50
+
+// DateLayout is how the bounds and answers of date markets are entered.
+const DateLayout = "2006-01-02"
+
+// marketLocation is the time zone market dates are entered in.
+func marketLocation() (*time.Location, error) {
+	return time.LoadLocation("Europe/Madrid")
+}
+
+// bucketRange is the range of a new bucket or date market and the outcomes
+// it is split into.
+type bucketRange struct {
+	Lower   *float64
+	Upper   *float64
+	Size    *float64
+	Buckets []models.Bucket
+}
+
+// numberBuckets splits lower up to upper into steps of size, the last one
+// cut short if it does not divide evenly, with a bucket below and above.
+func numberBuckets(lower, upper, size *float64) (bucketRange, error) {
+	if lower == nil || upper == nil || size == nil {
+		return bucketRange{}, ErrBucketRange
+	}
+
+	for _, f := range []float64{*lower, *upper, *size} {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return bucketRange{}, ErrBucketRange
+		}
+	}
+
+	if *lower >= *upper || *size <= 0 {
+		return bucketRange{}, ErrBucketRange
+	}
+
+	// Allow for float error so 0.3 in steps of 0.1 is three buckets. The
+	// count is checked before converting it, since a tiny step gives a
+	// ratio no int can hold.
+	steps := math.Ceil((*upper-*lower) / *size - 1e-9)
+	if steps > MaxBuckets {
+		return bucketRange{}, ErrBucketRange
+	}
+	n := int(steps)
+
+	buckets := []models.Bucket{{Label: "under " + formatNumber(*lower), Upper: lower}}
+	for i := 0; i < n; i++ {
+		lo := roundBound(*lower + float64(i)**size)
+		hi := math.Min(roundBound(*lower+float64(i+1)**size), *upper)
+		buckets = append(buckets, models.Bucket{
+			Label: formatNumber(lo) + " – " + formatNumber(hi),
+			Lower: &lo,
+			Upper: &hi,
+		})
+	}
+	buckets = append(buckets, models.Bucket{Label: formatNumber(*upper) + " and over", Lower: upper})
+
+	return bucketRange{Lower: lower, Upper: upper, Size: size, Buckets: buckets}, nil
+}
+
+// dateBuckets splits the days from start through end into buckets of days,
+// with a bucket before and after. Bounds are local midnights stored as Unix
+// timestamps, so buckets stay whole days across daylight saving changes.
+func dateBuckets(start, end string, days int) (bucketRange, error) {
+	loc, err := marketLocation()
+	if err != nil {
+		return bucketRange{}, err
+	}
+
+	from, err := time.ParseInLocation(DateLayout, start, loc)
+	if err != nil {
+		return bucketRange{}, ErrBucketDate
+	}
+
+	through, err := time.ParseInLocation(DateLayout, end, loc)
+	if err != nil {
+		return bucketRange{}, ErrBucketDate
+	}
+
+	if days < 1 || through.Before(from) {
+		return bucketRange{}, ErrBucketRange
+	}
+
+	stop := through.AddDate(0, 0, 1)
+	span := int(math.Round(stop.Sub(from).Hours() / 24))
+	if (span+days-1)/days > MaxBuckets {
+		return bucketRange{}, ErrBucketRange
+	}
+
+	lower, upper := unixValue(from), unixValue(stop)
+	size := float64(days * 24 * 60 * 60)
+
+	buckets := []models.Bucket{{Label: "before " + from.Format(DateLayout), Upper: &lower}}
+	for day := from; day.Before(stop); {
+		next := day.AddDate(0, 0, days)
+		if next.After(stop) {
+			next = stop
+		}
+
+		label := day.Format(DateLayout)
+		if last := next.AddDate(0, 0, -1); last.After(day) {
+			label += " – " + last.Format(DateLayout)
+		}
+
+		lo, hi := unixValue(day), unixValue(next)
+		buckets = append(buckets, models.Bucket{Label: label, Lower: &lo, Upper: &hi})
+		day = next
+	}
+	buckets = append(buckets, models.Bucket{Label: "after " + through.Format(DateLayout), Lower: &upper})
+
+	return bucketRange{Lower: &lower, Upper: &upper, Size: &size, Buckets: buckets}, nil
+}
+
+// DateValue turns a date entered for a date market into the value it is
+// resolved with.
+func DateValue(date string) (float64, error) {
+	loc, err := marketLocation()
+	if err != nil {
+		return 0, err
+	}
+
+	t, err := time.ParseInLocation(DateLayout, date, loc)
+	if err != nil {
+		return 0, ErrBucketDate
+	}
+
+	return unixValue(t), nil
+}
+
+// BucketShares resolves a bucket or date market entirely to the bucket value
+// falls in. m must have its outcomes loaded.
+func BucketShares(m models.Market, value float64) (map[uuid.UUID]int, error) {
+	if !m.Kind.Bucketed() {
+		return nil, ErrNotValueMarket
+	}
+
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil, ErrInvalidValue
+	}
+
+	for _, o := range m.Outcomes {
+		if (o.LowerBound == nil || value >= *o.LowerBound) && (o.UpperBound == nil || value < *o.UpperBound) {
+			return FullShare(o.ID), nil
+		}
+	}
+
+	return nil, ErrNotValueMarket
+}
+
+func unixValue(t time.Time) float64 {
+	return float64(t.Unix())
+}
+
+// roundBound drops the float error of adding up bucket sizes, e.g. turning
+// 0.30000000000000004 into 0.3.
+func roundBound(f float64) float64 {
+	return math.Round(f*1e9) / 1e9
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
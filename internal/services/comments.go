@@ -0,0 +1,239 @@
+package services
+
+import (
+	"errors"
+	"foresee/internal/models"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+var ErrCommentEmpty = errors.New("the comment cannot be empty")
+var ErrCommentTooLong = errors.New("comments cannot be longer than 4000 characters")
+var ErrCommentNotFound = errors.New("the comment does not exist")
+var ErrCommentNotAuthor = errors.New("only the author can change this comment")
+var ErrCommentDeleted = errors.New("this comment has been deleted")
+
+const (
+	CommentThreadsPerPage = 20
+	maxCommentLength      = 4000
+
+	// Replies deeper than this are rendered at the same indentation so long
+	// back-and-forths stay readable on narrow screens.
+	MaxCommentDepth = 4
+)
+
+type CommentService struct {
+	Comments      *models.CommentModel
+	Users         *models.UserModel
+	MarketService *MarketService
+}
+
+type ThreadedComment struct {
+	models.Comment
+	Depth   int
+	Deleted bool
+	Removed bool
+}
+
+type CommentPage struct {
+	Comments   []ThreadedComment
+	Page       int
+	TotalPages int
+}
+
+func (p CommentPage) HasPrevious() bool {
+	return p.Page > 1
+}
+
+func (p CommentPage) HasNext() bool {
+	return p.Page < p.TotalPages
+}
+
+func (p CommentPage) PreviousPage() int {
+	return p.Page - 1
+}
+
+func (p CommentPage) NextPage() int {
+	return p.Page + 1
+}
+
+// ForMarket returns the requested page of threads flattened depth-first so
+// templates can render them in order using Depth for indentation.
+func (s *CommentService) ForMarket(marketID uuid.UUID, page int) (CommentPage, error) {
+	total, err := s.Comments.CountThreads(marketID)
+	if err != nil {
+		return CommentPage{}, err
+	}
+
+	totalPages := max(1, (total+CommentThreadsPerPage-1)/CommentThreadsPerPage)
+	page = min(max(page, 1), totalPages)
+
+	rows, err := s.Comments.Threads(marketID, CommentThreadsPerPage, (page-1)*CommentThreadsPerPage)
+	if err != nil {
+		return CommentPage{}, err
+	}
+
+	return CommentPage{
+		Comments:   flattenThreads(rows),
+		Page:       page,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// flattenThreads expects rows ordered by creation time. Top-level comments
+// are shown newest first and replies oldest first.
+func flattenThreads(rows []models.Comment) []ThreadedComment {
+	children := make(map[uuid.UUID][]models.Comment)
+	var roots []models.Comment
+
+	for _, c := range rows {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+			continue
+		}
+		children[*c.ParentID] = append(children[*c.ParentID], c)
+	}
+
+	out := make([]ThreadedComment, 0, len(rows))
+
+	var walk func(c models.Comment, depth int)
+	walk = func(c models.Comment, depth int) {
+		tc := ThreadedComment{
+			Comment: c,
+			Depth:   min(depth, MaxCommentDepth),
+			Deleted: c.DeletedAt != nil,
+			Removed: c.RemovedBy != nil,
+		}
+		if tc.Deleted {
+			tc.Body = ""
+		}
+		out = append(out, tc)
+
+		for _, child := range children[c.ID] {
+			walk(child, depth+1)
+		}
+	}
+
+	for i := len(roots) - 1; i >= 0; i-- {
+		walk(roots[i], 0)
+	}
+
+	return out
+}
+
+// Post adds a comment to the market, replying to parentID when it is set.
+// A market that does not exist returns models.ErrNoRecord.
+func (s *CommentService) Post(marketID, userID uuid.UUID, parentID *uuid.UUID, body string) (uuid.UUID, error) {
+	body, err := cleanCommentBody(body)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	suspended, err := s.Users.IsSuspended(userID)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if suspended {
+		return uuid.UUID{}, ErrUserSuspended
+	}
+
+	market, err := s.MarketService.Markets.Get(marketID)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	allowed, err := s.MarketService.CanAccess(market, userID)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if !allowed {
+		return uuid.UUID{}, ErrNotGroupMember
+	}
+
+	if parentID != nil {
+		parent, err := s.Comments.Get(*parentID)
+		if err != nil {
+			if errors.Is(err, models.ErrNoRecord) {
+				return uuid.UUID{}, ErrCommentNotFound
+			}
+			return uuid.UUID{}, err
+		}
+
+		if parent.MarketID != marketID {
+			return uuid.UUID{}, ErrCommentNotFound
+		}
+	}
+
+	return s.Comments.Insert(marketID, userID, parentID, body)
+}
+
+func (s *CommentService) Edit(commentID, userID uuid.UUID, body string) (models.Comment, error) {
+	c, err := s.authored(commentID, userID)
+	if err != nil {
+		return c, err
+	}
+
+	body, err = cleanCommentBody(body)
+	if err != nil {
+		return c, err
+	}
+
+	return c, s.Comments.UpdateBody(commentID, body)
+}
+
+func (s *CommentService) Delete(commentID, userID uuid.UUID) (models.Comment, error) {
+	c, err := s.authored(commentID, userID)
+	if err != nil {
+		return c, err
+	}
+
+	return c, s.Comments.Delete(commentID, nil)
+}
+
+// authored returns the comment along with any error once it has been
+// loaded, so callers can still point the user back at it.
+func (s *CommentService) authored(commentID, userID uuid.UUID) (models.Comment, error) {
+	c, err := s.get(commentID)
+	if err != nil {
+		return models.Comment{}, err
+	}
+
+	if c.UserID != userID {
+		return c, ErrCommentNotAuthor
+	}
+
+	if c.DeletedAt != nil {
+		return c, ErrCommentDeleted
+	}
+
+	return c, nil
+}
+
+func (s *CommentService) get(commentID uuid.UUID) (models.Comment, error) {
+	c, err := s.Comments.Get(commentID)
+	if err != nil {
+		if errors.Is(err, models.ErrNoRecord) {
+			return models.Comment{}, ErrCommentNotFound
+		}
+		return models.Comment{}, err
+	}
+
+	return c, nil
+}
+
+func cleanCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(strings.ReplaceAll(body, "\r\n", "\n"))
+	if body == "" {
+		return "", ErrCommentEmpty
+	}
+
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return "", ErrCommentTooLong
+	}
+
+	return body, nil
+}
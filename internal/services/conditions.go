@@ -0,0 +1,138 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"foresee/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var ErrConditionOutcome = errors.New("a market can only depend on an outcome of an open market you can see")
+var ErrConditionGroup = errors.New("a conditional market must be in the same group as the market it depends on")
+var ErrConditionPending = errors.New("this market can only be resolved once the market it depends on is resolved")
+var ErrConditionNotMet = errors.New("the market this one depends on did not resolve to its outcome")
+
+// Condition looks up the market and outcome a new market created by userID
+// would depend on. Scalar markets are split between their outcomes rather
+// than won by one, so nothing can depend on them.
+func (s *MarketService) Condition(outcomeID, userID uuid.UUID) (models.Market, models.Outcome, error) {
+	o, err := s.OutcomeService.Outcomes.Get(outcomeID)
+	if err != nil {
+		if errors.Is(err, models.ErrNoRecord) {
+			return models.Market{}, models.Outcome{}, ErrConditionOutcome
+		}
+		return models.Market{}, models.Outcome{}, err
+	}
+
+	m, err := s.Get(o.MarketID)
+	if err != nil {
+		return models.Market{}, models.Outcome{}, err
+	}
+
+	if m.Status != "open" || m.Kind == models.KindScalar {
+		return models.Market{}, models.Outcome{}, ErrConditionOutcome
+	}
+
+	allowed, err := s.CanAccess(m, userID)
+	if err != nil {
+		return models.Market{}, models.Outcome{}, err
+	}
+
+	if !allowed {
+		return models.Market{}, models.Outcome{}, ErrConditionOutcome
+	}
+
+	return m, o, nil
+}
+
+// ConditionalOn lists the markets depending on marketID, leaving out those
+// hidden by a moderator.
+func (s *MarketService) ConditionalOn(marketID uuid.UUID) ([]models.Market, error) {
+	markets, err := s.Markets.ConditionalOn(marketID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := markets[:0]
+	for _, m := range markets {
+		if m.HiddenAt == nil {
+			visible = append(visible, m)
+		}
+	}
+
+	return visible, nil
+}
+
+// checkCondition returns ErrConditionPending while the market m depends on
+// is unresolved, and ErrConditionNotMet once it has resolved to another
+// outcome or been voided.
+func (s *MarketService) checkCondition(m models.Market) error {
+	if m.ConditionMarketID == nil {
+		return nil
+	}
+
+	parent, err := s.Markets.Get(*m.ConditionMarketID)
+	if err != nil {
+		return err
+	}
+
+	switch parent.Status {
+	case "resolved":
+		if parent.ResolvedOutcomeID == nil || *parent.ResolvedOutcomeID != *m.ConditionOutcomeID {
+			return ErrConditionNotMet
+		}
+		return nil
+	case "void":
+		return ErrConditionNotMet
+	default:
+		return ErrConditionPending
+	}
+}
+
+// voidConditions voids, with full refunds, the open and pending markets
+// depending on marketID unless it resolved to their outcome. Pass uuid.Nil
+// as outcomeID when marketID itself was voided. userID is recorded as
+// having voided them.
+func (s *MarketService) voidConditions(tx *sql.Tx, marketID, outcomeID, userID uuid.UUID) error {
+	children, err := s.Markets.OpenConditionalOn(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range children {
+		if *c.ConditionOutcomeID == outcomeID {
+			continue
+		}
+
+		err = s.Void(tx, c.ID, userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// conditionsChanged reports whether moving a resolution from the previous
+// leading outcome to next decides any of children differently. Markets
+// that depended on next were voided, and those that depended on previous
+// went ahead unless they were voided some other way.
+func conditionsChanged(children []models.Market, previous, next uuid.UUID) bool {
+	if previous == next {
+		return false
+	}
+
+	for _, c := range children {
+		switch *c.ConditionOutcomeID {
+		case next:
+			return true
+		case previous:
+			if c.Status != "void" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"foresee/internal/models"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestConditionsChanged(t *testing.T) {
+	yes, no, maybe := uuid.New(), uuid.New(), uuid.New()
+
+	child := func(outcomeID uuid.UUID, status string) models.Market {
+		return models.Market{ID: uuid.New(), Status: status, ConditionOutcomeID: &outcomeID}
+	}
+
+	tests := []struct {
+		name     string
+		children []models.Market
+		previous uuid.UUID
+		next     uuid.UUID
+		want     bool
+	}{
+		{
+			name:     "No children",
+			previous: yes,
+			next:     no,
+			want:     false,
+		},
+		{
+			name:     "Same leading outcome",
+			children: []models.Market{child(yes, "resolved"), child(no, "void")},
+			previous: yes,
+			next:     yes,
+			want:     false,
+		},
+		{
+			name:     "Child of the previous outcome went ahead",
+			children: []models.Market{child(yes, "open")},
+			previous: yes,
+			next:     no,
+			want:     true,
+		},
+		{
+			name:     "Child of the previous outcome was settled",
+			children: []models.Market{child(yes, "resolved")},
+			previous: yes,
+			next:     no,
+			want:     true,
+		},
+		{
+			name:     "Child of the previous outcome was voided anyway",
+			children: []models.Market{child(yes, "void")},
+			previous: yes,
+			next:     no,
+			want:     false,
+		},
+		{
+			name:     "Child of the new outcome was voided",
+			children: []models.Market{child(no, "void")},
+			previous: yes,
+			next:     no,
+			want:     true,
+		},
+		{
+			name:     "Child of another outcome stays void",
+			children: []models.Market{child(maybe, "void")},
+			previous: yes,
+			next:     no,
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := conditionsChanged(tt.children, tt.previous, tt.next)
+			if got != tt.want {
+				t.Errorf("conditionsChanged() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"errors"
+	"foresee/internal/models"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrMarketNotResolved = errors.New("only resolved markets can be corrected")
+var ErrCorrectionSameOutcome = errors.New("the market is already resolved to that outcome")
+var ErrCorrectionSameValue = errors.New("the market is already resolved to that value")
+var ErrCorrectionReason = errors.New("please give a reason for the correction")
+var ErrCorrectionConditions = errors.New("other markets depend on how this market resolved, so its resolution cannot be corrected")
+
+// Correct re-resolves a settled market to outcomeID, or to value for markets
+// answered with a number. In one transaction it claws back every payout,
+// liquidity return and fee of the previous settlement, settles the market
+// again and records how each bet's payout changed. Balances are allowed to
+// go negative when a user has already spent a payout that is taken back.
+//
+// Markets depending on this one were voided or went ahead when it resolved,
+// and bets on them may have been settled since, so a correction that would
+// decide any of them differently is refused.
+func (s *MarketService) Correct(marketID, adminID, outcomeID uuid.UUID, value *float64, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return ErrCorrectionReason
+	}
+
+	tx, err := s.Markets.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	m, err := s.Markets.SelectForUpdate(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	if m.Status != "resolved" || m.ResolvedOutcomeID == nil {
+		return ErrMarketNotResolved
+	}
+
+	if m.Kind.ResolvedByValue() {
+		if value != nil && m.ResolvedValue != nil && *value == *m.ResolvedValue {
+			return ErrCorrectionSameValue
+		}
+	} else {
+		previousShares, err := s.OutcomeService.Outcomes.ResolvedShares(tx, marketID)
+		if err != nil {
+			return err
+		}
+
+		// A split resolution can be corrected to its leading outcome alone.
+		if *m.ResolvedOutcomeID == outcomeID && len(previousShares) <= 1 {
+			return ErrCorrectionSameOutcome
+		}
+
+		inMarket, err := s.OutcomeService.ExistsForMarketTx(tx, outcomeID, marketID)
+		if err != nil {
+			return err
+		}
+
+		if !inMarket {
+			return models.ErrOutcomeDoesNotBelongToMarket
+		}
+	}
+
+	shares, err := s.rulingShares(m, outcomeID, value)
+	if err != nil {
+		return err
+	}
+
+	children, err := s.Markets.ConditionalOn(marketID)
+	if err != nil {
+		return err
+	}
+
+	if conditionsChanged(children, *m.ResolvedOutcomeID, leadingOutcome(shares)) {
+		return ErrCorrectionConditions
+	}
+
+	bets, err := s.BetService.ForMarketForUpdate(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	previous := make(map[uuid.UUID]int, len(bets))
+	for _, b := range bets {
+		if b.Payout == nil {
+			continue
+		}
+		previous[b.ID] = *b.Payout
+
+		// Archived-season payouts were never credited, see Settle.
+		if *b.Payout == 0 || b.SeasonClosed {
+			continue
+		}
+
+		err = s.UserService.DecreaseBalanceBy(tx, b.UserID, *b.Payout)
+		if err != nil {
+			return err
+		}
+	}
+
+	positions, err := s.Liquidity.ForMarketForUpdate(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range positions {
+		if p.Payout == nil || *p.Payout == 0 || p.UserID == nil || p.SeasonClosed {
+			continue
+		}
+
+		err = s.UserService.DecreaseBalanceBy(tx, *p.UserID, *p.Payout)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.refundFees(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	err = s.Markets.ClearResolution(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	err = s.Markets.SetResolvedValue(tx, marketID, value)
+	if err != nil {
+		return err
+	}
+
+	settled, err := s.Settle(tx, marketID, shares, adminID)
+	if err != nil {
+		return err
+	}
+
+	correctionID, err := s.Corrections.Insert(tx, models.ResolutionCorrection{
+		MarketID:          marketID,
+		PreviousOutcomeID: *m.ResolvedOutcomeID,
+		OutcomeID:         leadingOutcome(shares),
+		Reason:            reason,
+		CorrectedBy:       adminID,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, b := range settled {
+		if previous[b.BetID] == b.Payout {
+			continue
+		}
+
+		err = s.Corrections.InsertPayout(tx, correctionID, b.BetID, b.UserID, previous[b.BetID], b.Payout)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	s.Events.Publish(ResolutionCorrected{
+		MarketID:    marketID,
+		OutcomeID:   leadingOutcome(shares),
+		CorrectedBy: adminID,
+		Bets:        settled,
+	})
+
+	return nil
+}
+
+func (s *MarketService) CorrectionHistory(marketID uuid.UUID) ([]models.ResolutionCorrection, error) {
+	return s.Corrections.ForMarket(marketID)
+}
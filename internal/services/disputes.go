@@ -0,0 +1,324 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"foresee/internal/models"
+	"foresee/internal/payout"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrDisputeWindowClosed = errors.New("this resolution can no longer be disputed")
+var ErrDisputeNotBettor = errors.New("only bettors on this market can dispute its resolution")
+var ErrDisputeJustification = errors.New("please explain why the proposed outcome is wrong")
+var ErrMarketNotDisputed = errors.New("this market has no open dispute")
+var ErrInvalidDisputeDecision = errors.New("overturning a resolution requires a different outcome or value")
+var ErrConflictOfInterest = errors.New("you proposed this resolution or hold a position in the market, another admin must decide it")
+
+type DisputeService struct {
+	Disputes      *models.DisputeModel
+	Bets          *models.BetModel
+	MarketService *MarketService
+}
+
+// DisputeCase is a disputed market awaiting an admin ruling.
+type DisputeCase struct {
+	Market        models.Market
+	ProposedLabel string
+	Disputes      []models.Dispute
+
+	// Conflicted is set when the admin viewing the queue may not decide
+	// the case, see Decide.
+	Conflicted bool
+}
+
+// File disputes the proposed outcome of a market. The first dispute moves
+// the market out of the automatic finalization queue and into the admin's.
+func (s *DisputeService) File(marketID, userID uuid.UUID, justification string) error {
+	justification = strings.TrimSpace(justification)
+	if justification == "" {
+		return ErrDisputeJustification
+	}
+
+	hasBet, err := s.Bets.Exists(marketID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !hasBet {
+		return ErrDisputeNotBettor
+	}
+
+	tx, err := s.Disputes.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	m, err := s.MarketService.Markets.SelectForUpdate(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	if m.Status != "proposed" && m.Status != "disputed" {
+		return ErrDisputeWindowClosed
+	}
+
+	if m.Status == "proposed" && m.FinalizesAt != nil && !time.Now().Before(*m.FinalizesAt) {
+		return ErrDisputeWindowClosed
+	}
+
+	err = s.Disputes.Insert(tx, marketID, userID, justification)
+	if err != nil {
+		return err
+	}
+
+	err = s.MarketService.Markets.MarkDisputed(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CanDispute reports whether userID may still dispute the market's proposed
+// outcome.
+func (s *DisputeService) CanDispute(m models.Market, disputes []models.Dispute, userID uuid.UUID) (bool, error) {
+	if userID == uuid.Nil {
+		return false, nil
+	}
+
+	switch m.Status {
+	case "proposed":
+		if m.FinalizesAt != nil && !time.Now().Before(*m.FinalizesAt) {
+			return false, nil
+		}
+	case "disputed":
+	default:
+		return false, nil
+	}
+
+	for _, d := range disputes {
+		if d.UserID != nil && *d.UserID == userID {
+			return false, nil
+		}
+	}
+
+	return s.Bets.Exists(m.ID, userID)
+}
+
+func (s *DisputeService) ForMarket(marketID uuid.UUID) ([]models.Dispute, error) {
+	return s.Disputes.ForMarket(marketID)
+}
+
+// Queue lists the disputed markets for adminID to decide.
+func (s *DisputeService) Queue(adminID uuid.UUID) ([]DisputeCase, error) {
+	ids, err := s.MarketService.Markets.Disputed()
+	if err != nil {
+		return nil, err
+	}
+
+	cases := make([]DisputeCase, 0, len(ids))
+	for _, id := range ids {
+		m, err := s.MarketService.Get(id)
+		if err != nil {
+			return nil, err
+		}
+
+		disputes, err := s.Disputes.ForMarket(id)
+		if err != nil {
+			return nil, err
+		}
+
+		c := DisputeCase{Market: m, Disputes: disputes}
+		c.ProposedLabel = resolutionLabel(m)
+
+		c.Conflicted, err = s.conflicted(m, adminID)
+		if err != nil {
+			return nil, err
+		}
+
+		cases = append(cases, c)
+	}
+
+	return cases, nil
+}
+
+// Decide settles a disputed market. Upholding pays out the proposed
+// resolution and credits it to its proposer; overturning resolves the market
+// entirely to outcomeID, or to value for markets answered with a number, and
+// credits the admin. Admins cannot decide on a
+// resolution they proposed or on a market they hold a position in.
+func (s *DisputeService) Decide(marketID, adminID uuid.UUID, decision models.DisputeStatus, outcomeID uuid.UUID, value *float64) error {
+	tx, err := s.Disputes.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	m, err := s.MarketService.Markets.SelectForUpdate(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	if m.Status != "disputed" || m.ProposedOutcomeID == nil || m.ProposedBy == nil {
+		return ErrMarketNotDisputed
+	}
+
+	conflicted, err := s.conflicted(m, adminID)
+	if err != nil {
+		return err
+	}
+
+	if conflicted {
+		return ErrConflictOfInterest
+	}
+
+	proposed, err := s.proposedShares(tx, m)
+	if err != nil {
+		return err
+	}
+
+	shares, resolvedBy := proposed, *m.ProposedBy
+
+	switch decision {
+	case models.DisputeUpheld:
+		// Settle the proposal as it stands.
+
+	case models.DisputeOverturned:
+		if m.Kind.ResolvedByValue() {
+			if value != nil && m.ResolvedValue != nil && *value == *m.ResolvedValue {
+				return ErrInvalidDisputeDecision
+			}
+		} else {
+			if len(proposed) == 1 && proposed[outcomeID] == payout.FullShare {
+				return ErrInvalidDisputeDecision
+			}
+
+			inMarket, err := s.MarketService.OutcomeService.ExistsForMarketTx(tx, outcomeID, marketID)
+			if err != nil {
+				return err
+			}
+
+			if !inMarket {
+				return models.ErrOutcomeDoesNotBelongToMarket
+			}
+		}
+
+		shares, err = s.MarketService.rulingShares(m, outcomeID, value)
+		if err != nil {
+			return err
+		}
+		resolvedBy = adminID
+
+		err = s.MarketService.Markets.SetResolvedValue(tx, marketID, value)
+		if err != nil {
+			return err
+		}
+
+	default:
+		return ErrInvalidDisputeDecision
+	}
+
+	err = s.Disputes.Decide(tx, marketID, decision, adminID)
+	if err != nil {
+		return err
+	}
+
+	return s.settle(tx, m, shares, resolvedBy)
+}
+
+// conflicted reports whether adminID proposed m's resolution or stands to
+// gain from it.
+func (s *DisputeService) conflicted(m models.Market, adminID uuid.UUID) (bool, error) {
+	if m.ProposedBy != nil && *m.ProposedBy == adminID {
+		return true, nil
+	}
+
+	return s.MarketService.holdsPosition(m.ID, adminID)
+}
+
+// FinalizeDue settles every proposed market whose dispute window closed
+// without a dispute. It runs as a background job, so a market that fails
+// does not hold up the rest; the failures are returned together.
+func (s *DisputeService) FinalizeDue() error {
+	ids, err := s.MarketService.Markets.DueProposals()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, id := range ids {
+		err = s.finalize(id)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("market %s: %w", id, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *DisputeService) finalize(marketID uuid.UUID) error {
+	tx, err := s.Disputes.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	m, err := s.MarketService.Markets.SelectForUpdate(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	// A dispute may have been filed since the market was listed as due.
+	if m.Status != "proposed" || m.ProposedOutcomeID == nil || m.ProposedBy == nil {
+		return nil
+	}
+
+	shares, err := s.proposedShares(tx, m)
+	if err != nil {
+		return err
+	}
+
+	return s.settle(tx, m, shares, *m.ProposedBy)
+}
+
+// proposedShares returns the split stored with the proposal. Proposals
+// without one resolve entirely to the proposed outcome.
+func (s *DisputeService) proposedShares(tx *sql.Tx, m models.Market) (map[uuid.UUID]int, error) {
+	shares, err := s.MarketService.OutcomeService.Outcomes.ResolvedShares(tx, m.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(shares) == 0 {
+		return FullShare(*m.ProposedOutcomeID), nil
+	}
+
+	return shares, nil
+}
+
+func (s *DisputeService) settle(tx *sql.Tx, m models.Market, shares map[uuid.UUID]int, resolvedBy uuid.UUID) error {
+	settled, err := s.MarketService.Settle(tx, m.ID, shares, resolvedBy)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	s.MarketService.Events.Publish(MarketResolved{
+		MarketID:   m.ID,
+		OutcomeID:  leadingOutcome(shares),
+		ResolvedBy: manualResolver(m, resolvedBy),
+		Bets:       settled,
+	})
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"errors"
+	"foresee/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrEventNotFound = errors.New("the event must exist")
+var ErrEventDate = errors.New("the event date must be valid")
+var ErrEventGroupKind = errors.New("only yes/no markets can be mutually exclusive")
+
+// EventService manages the event pages related markets are grouped under.
+// It is unrelated to the Events bus.
+type EventService struct {
+	Events        *models.EventModel
+	MarketService *MarketService
+}
+
+type EventPage struct {
+	models.Event
+	Markets []*models.Market
+}
+
+// Create adds an event taking place on date, formatted as DateLayout.
+func (s *EventService) Create(title, description, date string, userID uuid.UUID) (uuid.UUID, error) {
+	startsOn, err := time.Parse(DateLayout, date)
+	if err != nil {
+		return uuid.UUID{}, ErrEventDate
+	}
+
+	suspended, err := s.MarketService.UserService.Users.IsSuspended(userID)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if suspended {
+		return uuid.UUID{}, ErrUserSuspended
+	}
+
+	return s.Events.Insert(title, description, startsOn, userID)
+}
+
+// Find returns the event without its markets.
+func (s *EventService) Find(eventID uuid.UUID) (models.Event, error) {
+	return s.Events.Get(eventID)
+}
+
+// Get returns the event with the markets viewerID can see, outcomes
+// included.
+func (s *EventService) Get(eventID, viewerID uuid.UUID) (EventPage, error) {
+	event, err := s.Events.Get(eventID)
+	if err != nil {
+		return EventPage{}, err
+	}
+
+	markets, err := s.MarketService.Markets.ForEvent(eventID, viewerID)
+	if err != nil {
+		return EventPage{}, err
+	}
+
+	markets, err = s.MarketService.withOutcomes(markets)
+	if err != nil {
+		return EventPage{}, err
+	}
+
+	return EventPage{Event: event, Markets: markets}, nil
+}
+
+func (s *EventService) Upcoming() ([]models.Event, error) {
+	return s.Events.Upcoming()
+}
+
+func (s *EventService) Past() ([]models.Event, error) {
+	return s.Events.Past()
+}
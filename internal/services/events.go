@@ -0,0 +1,116 @@
+package services
+
+import (
+	"log"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+type Event interface {
+	EventName() string
+}
+
+type BetPlaced struct {
+	UserID             uuid.UUID
+	MarketID           uuid.UUID
+	OutcomeID          uuid.UUID
+	Amount             int
+	ImpliedProbability float64
+}
+
+func (BetPlaced) EventName() string { return "bet.placed" }
+
+type SettledBet struct {
+	BetID              uuid.UUID
+	UserID             uuid.UUID
+	OutcomeID          uuid.UUID
+	Amount             int
+	Payout             int
+	ImpliedProbability *float64
+}
+
+type MarketResolved struct {
+	MarketID   uuid.UUID
+	OutcomeID  uuid.UUID
+	ResolvedBy *uuid.UUID
+	Bets       []SettledBet
+}
+
+func (MarketResolved) EventName() string { return "market.resolved" }
+
+// ResolutionCorrected is published when an admin re-resolves a settled
+// market. Bets holds every bet as settled again.
+type ResolutionCorrected struct {
+	MarketID    uuid.UUID
+	OutcomeID   uuid.UUID
+	CorrectedBy uuid.UUID
+	Bets        []SettledBet
+}
+
+func (ResolutionCorrected) EventName() string { return "market.corrected" }
+
+// ResolverRequested is published when a market is created with a
+// designated resolver, who has to accept before it opens.
+type ResolverRequested struct {
+	MarketID    uuid.UUID
+	MarketTitle string
+	CreatedBy   uuid.UUID
+	ResolverID  uuid.UUID
+}
+
+func (ResolverRequested) EventName() string { return "resolver.requested" }
+
+type ResolverAnswered struct {
+	MarketID    uuid.UUID
+	MarketTitle string
+	CreatedBy   uuid.UUID
+	ResolverID  uuid.UUID
+	Accepted    bool
+}
+
+func (ResolverAnswered) EventName() string { return "resolver.answered" }
+
+type DailyRewardClaimed struct {
+	UserID uuid.UUID
+	Amount int
+	Streak int
+}
+
+func (DailyRewardClaimed) EventName() string { return "reward.claimed" }
+
+type EventHandler func(Event) error
+
+// Events is a synchronous, in-process event bus. Services publish after
+// their transaction has committed, so handlers only ever see facts and a
+// failing handler is logged rather than undoing the original operation.
+type Events struct {
+	ErrorLog *log.Logger
+
+	mu       sync.RWMutex
+	handlers []EventHandler
+}
+
+func (e *Events) Subscribe(h EventHandler) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.handlers = append(e.handlers, h)
+}
+
+func (e *Events) Publish(ev Event) {
+	if e == nil {
+		return
+	}
+
+	e.mu.RLock()
+	handlers := e.handlers
+	e.mu.RUnlock()
+
+	for _, h := range handlers {
+		err := h(ev)
+		if err != nil && e.ErrorLog != nil {
+			e.ErrorLog.Printf("event %s: %v", ev.EventName(), err)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"database/sql"
+	"foresee/internal/models"
+	"foresee/internal/payout"
+
+	"github.com/google/uuid"
+)
+
+type FeeService struct {
+	Fees *models.FeeModel
+}
+
+func (s *FeeService) Schedule() (models.FeeSchedule, error) {
+	return s.Fees.Schedule()
+}
+
+func (s *FeeService) UpdateSchedule(fs models.FeeSchedule, adminID uuid.UUID) error {
+	return s.Fees.UpdateSchedule(fs, adminID)
+}
+
+func (s *FeeService) Treasury() (int, error) {
+	return s.Fees.Treasury()
+}
+
+func (s *FeeService) Latest() ([]models.FeeEntry, error) {
+	return s.Fees.Latest()
+}
+
+// IncomeFor lists the creator fees userID has earned, including corrections
+// that took them back.
+func (s *FeeService) IncomeFor(userID uuid.UUID) ([]models.FeeEntry, error) {
+	return s.Fees.ForUser(userID)
+}
+
+func marketFees(m models.Market) payout.Fees {
+	return payout.Fees{PlatformBps: m.PlatformFeeBps, CreatorBps: m.CreatorFeeBps}
+}
+
+// chargeFees credits a settlement's fees to the treasury and the creator.
+func (s *MarketService) chargeFees(tx *sql.Tx, m models.Market, plan payout.Plan) error {
+	if plan.PlatformFee > 0 {
+		err := s.Fees.Insert(tx, m.ID, models.FeePlatform, nil, plan.PlatformFee)
+		if err != nil {
+			return err
+		}
+	}
+
+	if plan.CreatorFee > 0 {
+		err := s.Fees.Insert(tx, m.ID, models.FeeCreator, &m.CreatedBy, plan.CreatorFee)
+		if err != nil {
+			return err
+		}
+
+		err = s.UserService.IncreaseBalanceBy(tx, m.CreatedBy, plan.CreatorFee)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// refundFees gives back the fees of a previous settlement before a market is
+// settled again. Like clawed back payouts, the creator's balance may go
+// negative.
+func (s *MarketService) refundFees(tx *sql.Tx, marketID uuid.UUID) error {
+	entries, err := s.Fees.NetForMarket(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		err = s.Fees.Insert(tx, marketID, e.Kind, e.UserID, -e.Amount)
+		if err != nil {
+			return err
+		}
+
+		if e.Kind != models.FeeCreator || e.UserID == nil {
+			continue
+		}
+
+		err = s.UserService.DecreaseBalanceBy(tx, *e.UserID, e.Amount)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"foresee/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrGroupPermission = errors.New("only group owners and admins can do that")
+var ErrInviteInvalid = errors.New("this invite link has expired or has already been used")
+var ErrInvalidGroupRole = errors.New("the owner role cannot be assigned or changed")
+
+type GroupService struct {
+	Groups        *models.GroupModel
+	MarketService *MarketService
+}
+
+type GroupPage struct {
+	models.Group
+	Role        models.GroupRole
+	Members     []models.GroupMembership
+	Invites     []models.GroupInvite
+	Markets     []*models.Market
+	Leaderboard []models.GroupStanding
+}
+
+func (s *GroupService) Create(name, description string, ownerID uuid.UUID) (uuid.UUID, error) {
+	tx, err := s.Groups.DB.Begin()
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	defer tx.Rollback()
+
+	id, err := s.Groups.Insert(tx, name, description, ownerID)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	_, err = s.Groups.AddMember(tx, id, ownerID, models.GroupOwner)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (s *GroupService) ForUser(userID uuid.UUID) ([]models.Group, error) {
+	return s.Groups.ForUser(userID)
+}
+
+// Get returns the group page for a member. Non-members get
+// ErrNotGroupMember so handlers can answer with a 404 and not reveal that
+// the group exists.
+func (s *GroupService) Get(groupID, userID uuid.UUID) (GroupPage, error) {
+	role, err := s.role(groupID, userID)
+	if err != nil {
+		return GroupPage{}, err
+	}
+
+	group, err := s.Groups.Get(groupID)
+	if err != nil {
+		return GroupPage{}, err
+	}
+
+	page := GroupPage{Group: group, Role: role}
+
+	page.Members, err = s.Groups.Members(groupID)
+	if err != nil {
+		return GroupPage{}, err
+	}
+
+	if role.CanManage() {
+		page.Invites, err = s.Groups.Invites(groupID)
+		if err != nil {
+			return GroupPage{}, err
+		}
+	}
+
+	page.Markets, err = s.MarketService.ForGroup(groupID)
+	if err != nil {
+		return GroupPage{}, err
+	}
+
+	page.Leaderboard, err = s.Groups.Leaderboard(groupID)
+	if err != nil {
+		return GroupPage{}, err
+	}
+
+	return page, nil
+}
+
+// CreateInvite issues a new invite link. A nil maxUses makes the link
+// reusable until it expires.
+func (s *GroupService) CreateInvite(groupID, userID uuid.UUID, validFor time.Duration, maxUses *int) (string, error) {
+	role, err := s.role(groupID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if !role.CanManage() {
+		return "", ErrGroupPermission
+	}
+
+	token, err := inviteToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = s.Groups.InsertInvite(groupID, userID, token, time.Now().Add(validFor), maxUses)
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func (s *GroupService) RevokeInvite(groupID, inviteID, userID uuid.UUID) error {
+	role, err := s.role(groupID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !role.CanManage() {
+		return ErrGroupPermission
+	}
+
+	return s.Groups.RevokeInvite(groupID, inviteID)
+}
+
+func (s *GroupService) Invite(token string) (models.GroupInvite, error) {
+	invite, err := s.Groups.InviteByToken(token)
+	if err != nil {
+		if errors.Is(err, models.ErrNoRecord) {
+			return models.GroupInvite{}, ErrInviteInvalid
+		}
+		return models.GroupInvite{}, err
+	}
+
+	if !invite.Usable(time.Now()) {
+		return models.GroupInvite{}, ErrInviteInvalid
+	}
+
+	return invite, nil
+}
+
+// Join redeems an invite. Existing members keep their role and do not use
+// up the invite.
+func (s *GroupService) Join(token string, userID uuid.UUID) (uuid.UUID, error) {
+	tx, err := s.Groups.DB.Begin()
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	defer tx.Rollback()
+
+	invite, err := s.Groups.InviteForUpdate(tx, token)
+	if err != nil {
+		if errors.Is(err, models.ErrNoRecord) {
+			return uuid.UUID{}, ErrInviteInvalid
+		}
+		return uuid.UUID{}, err
+	}
+
+	if !invite.Usable(time.Now()) {
+		return uuid.UUID{}, ErrInviteInvalid
+	}
+
+	added, err := s.Groups.AddMember(tx, invite.GroupID, userID, models.GroupMember)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if added {
+		err = s.Groups.UseInvite(tx, invite.ID)
+		if err != nil {
+			return uuid.UUID{}, err
+		}
+	}
+
+	return invite.GroupID, tx.Commit()
+}
+
+// SetRole lets owners and admins promote members to admin or demote them.
+// Ownership is fixed at creation.
+func (s *GroupService) SetRole(groupID, actorID, memberID uuid.UUID, role models.GroupRole) error {
+	if role == models.GroupOwner {
+		return ErrInvalidGroupRole
+	}
+
+	actorRole, err := s.role(groupID, actorID)
+	if err != nil {
+		return err
+	}
+
+	if !actorRole.CanManage() {
+		return ErrGroupPermission
+	}
+
+	current, err := s.Groups.MemberRole(groupID, memberID)
+	if err != nil {
+		return err
+	}
+
+	if current == models.GroupOwner {
+		return ErrInvalidGroupRole
+	}
+
+	return s.Groups.SetRole(groupID, memberID, role)
+}
+
+func (s *GroupService) role(groupID, userID uuid.UUID) (models.GroupRole, error) {
+	role, err := s.Groups.MemberRole(groupID, userID)
+	if err != nil {
+		if errors.Is(err, models.ErrNoRecord) {
+			return "", ErrNotGroupMember
+		}
+		return "", err
+	}
+
+	return role, nil
+}
+
+func inviteToken() (string, error) {
+	b := make([]byte, 18)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
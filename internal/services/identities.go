@@ -0,0 +1,135 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"foresee/internal/models"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+var ErrIdentityEmailMissing = errors.New("your identity provider did not share an email address")
+var ErrIdentityEmailNotVerified = errors.New("an account with this email already exists, but your identity provider has not verified the email")
+var ErrIdentityEmailUnverified = errors.New("your identity provider has not verified your email address")
+var ErrIdentityConcurrentLogin = errors.New("this account was being signed in from somewhere else at the same time, please try again")
+
+const minUsernameLength = 4
+const maxUsernameAttempts = 50
+
+type ExternalIdentity struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+}
+
+// LoginWithIdentity returns the user linked to the identity, linking an
+// existing account by verified email or provisioning a new one when needed.
+// Unverified emails neither link nor provision an account, so nobody can
+// claim an address they do not own.
+func (s *UserService) LoginWithIdentity(identity ExternalIdentity) (uuid.UUID, error) {
+	id, err := s.Users.IDForIdentity(identity.Issuer, identity.Subject)
+	if err == nil {
+		return id, nil
+	}
+
+	if !errors.Is(err, models.ErrNoRecord) {
+		return uuid.UUID{}, err
+	}
+
+	if identity.Email == "" {
+		return uuid.UUID{}, ErrIdentityEmailMissing
+	}
+
+	tx, err := s.Users.DB.Begin()
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+	defer tx.Rollback()
+
+	id, err = s.Users.IDForEmail(tx, identity.Email)
+	switch {
+	case err == nil:
+		if !identity.EmailVerified {
+			return uuid.UUID{}, ErrIdentityEmailNotVerified
+		}
+
+	case errors.Is(err, models.ErrNoRecord):
+		if !identity.EmailVerified {
+			return uuid.UUID{}, ErrIdentityEmailUnverified
+		}
+
+		username, err := s.availableUsername(tx, identity)
+		if err != nil {
+			return uuid.UUID{}, err
+		}
+
+		id, err = s.Users.InsertExternal(tx, username, identity.Email)
+		if errors.Is(err, models.ErrEmailAlreadyExists) || errors.Is(err, models.ErrUsernameAlreadyExists) {
+			// Another login provisioned the account since it was looked up.
+			return uuid.UUID{}, ErrIdentityConcurrentLogin
+		}
+		if err != nil {
+			return uuid.UUID{}, err
+		}
+
+	default:
+		return uuid.UUID{}, err
+	}
+
+	err = s.Users.LinkIdentity(tx, id, identity.Issuer, identity.Subject)
+	if errors.Is(err, models.ErrIdentityAlreadyLinked) {
+		return uuid.UUID{}, ErrIdentityConcurrentLogin
+	}
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return id, tx.Commit()
+}
+
+func (s *UserService) availableUsername(tx *sql.Tx, identity ExternalIdentity) (string, error) {
+	base := sanitizeUsername(identity.PreferredUsername)
+	if base == "" {
+		local, _, _ := strings.Cut(identity.Email, "@")
+		base = sanitizeUsername(local)
+	}
+
+	for len(base) < minUsernameLength {
+		base += "_"
+	}
+
+	candidate := base
+	for i := 1; i <= maxUsernameAttempts; i++ {
+		exists, err := s.Users.UsernameExists(tx, candidate)
+		if err != nil {
+			return "", err
+		}
+
+		if !exists {
+			return candidate, nil
+		}
+
+		candidate = fmt.Sprintf("%s%d", base, i+1)
+	}
+
+	return "", models.ErrUsernameAlreadyExists
+}
+
+func sanitizeUsername(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+		case r == '_' || r == '-' || r == '.':
+			b.WriteRune('_')
+		}
+	}
+
+	return strings.Trim(b.String(), "_")
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"database/sql"
+	"foresee/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IntegrityService keeps the integrity rule copied onto new markets.
+type IntegrityService struct {
+	Integrity *models.IntegrityModel
+}
+
+func (s *IntegrityService) Rule() (models.IntegrityRule, error) {
+	return s.Integrity.Rule()
+}
+
+func (s *IntegrityService) UpdateRule(rule models.IntegrityRule, adminID uuid.UUID) error {
+	return s.Integrity.UpdateRule(rule, adminID)
+}
+
+// checkResolverBet applies m's integrity rule to a bet userID is about to
+// place and reports whether the bet must be flagged. Designated resolvers
+// never bet, whatever the rule, and admins count as the resolvers of admin
+// markets. Oracle markets answer from their data source, so only their
+// resolution is checked.
+func (s *MarketService) checkResolverBet(m models.Market, userID uuid.UUID) (bool, error) {
+	if IsDesignatedResolver(m, userID) {
+		return false, ErrResolverCannotBet
+	}
+
+	if m.IntegrityRule != models.IntegrityBlock && m.IntegrityRule != models.IntegrityFlag {
+		return false, nil
+	}
+
+	if m.ResolverType == models.ResolverOracle {
+		return false, nil
+	}
+
+	resolver, err := s.CanResolve(m, userID)
+	if err != nil || !resolver {
+		return false, err
+	}
+
+	if m.IntegrityRule == models.IntegrityBlock {
+		return false, ErrResolverCannotBet
+	}
+
+	return true, nil
+}
+
+// needsReview reports whether userID resolving m must go to the admins: the
+// market is under IntegrityReview and userID holds a position in it.
+func (s *MarketService) needsReview(m models.Market, userID uuid.UUID) (bool, error) {
+	if m.IntegrityRule != models.IntegrityReview {
+		return false, nil
+	}
+
+	return s.holdsPosition(m.ID, userID)
+}
+
+// holdsPosition reports whether userID bet on the market or seeded liquidity
+// into it, either of which is paid out by its resolution.
+func (s *MarketService) holdsPosition(marketID, userID uuid.UUID) (bool, error) {
+	hasBet, err := s.BetService.Bets.Exists(marketID, userID)
+	if err != nil || hasBet {
+		return hasBet, err
+	}
+
+	return s.Liquidity.Exists(marketID, userID)
+}
+
+// review proposes the resolution and disputes it in the platform's name, so
+// nothing is paid out until an admin upholds or overturns it. It commits tx.
+func (s *MarketService) review(tx *sql.Tx, m models.Market, userID uuid.UUID, shares map[uuid.UUID]int) error {
+	err := s.OutcomeService.Outcomes.SetResolvedShares(tx, m.ID, shares)
+	if err != nil {
+		return err
+	}
+
+	err = s.Markets.Propose(tx, m.ID, userID, leadingOutcome(shares), time.Now())
+	if err != nil {
+		return err
+	}
+
+	err = s.Markets.MarkDisputed(tx, m.ID)
+	if err != nil {
+		return err
+	}
+
+	err = s.Disputes.InsertReview(tx, m.ID, "The resolver holds a position in this market, so an admin checks the resolution before it is paid out.")
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"foresee/internal/models"
+
+	"github.com/google/uuid"
+)
+
+type LeaderboardService struct {
+	Leaderboard *models.LeaderboardModel
+	Seasons     *SeasonService
+}
+
+type Leaderboard struct {
+	Period   string
+	Category string
+	Sort     models.LeaderboardSort
+	Months   []string
+	Entries  []models.LeaderboardEntry
+
+	PastSeasons []models.Season
+	Season      *models.Season
+	Standings   []models.SeasonStanding
+}
+
+const leaderboardSize = 50
+
+func (s *LeaderboardService) Get(period string, category string, sort models.LeaderboardSort) (Leaderboard, error) {
+	entries, err := s.Leaderboard.Ranking(period, category, sort, leaderboardSize)
+	if err != nil {
+		return Leaderboard{}, err
+	}
+
+	months, err := s.Leaderboard.Months()
+	if err != nil {
+		return Leaderboard{}, err
+	}
+
+	seasons, err := s.Seasons.Archived()
+	if err != nil {
+		return Leaderboard{}, err
+	}
+
+	return Leaderboard{
+		Period:      period,
+		Category:    category,
+		Sort:        sort,
+		Months:      months,
+		Entries:     entries,
+		PastSeasons: seasons,
+	}, nil
+}
+
+func (s *LeaderboardService) GetSeason(seasonID uuid.UUID) (Leaderboard, error) {
+	season, err := s.Seasons.Seasons.Get(seasonID)
+	if err != nil {
+		return Leaderboard{}, err
+	}
+
+	standings, err := s.Seasons.Standings(seasonID)
+	if err != nil {
+		return Leaderboard{}, err
+	}
+
+	seasons, err := s.Seasons.Archived()
+	if err != nil {
+		return Leaderboard{}, err
+	}
+
+	return Leaderboard{
+		Period:      models.PeriodAllTime,
+		Sort:        models.SortByProfit,
+		PastSeasons: seasons,
+		Season:      &season,
+		Standings:   standings,
+	}, nil
+}
+
+func (s *LeaderboardService) Refresh() error {
+	return s.Leaderboard.Refresh()
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"foresee/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var ErrLiquidityAmount = errors.New("liquidity cannot be negative")
+var ErrTreasuryNotAdmin = errors.New("only admins can seed markets from the treasury")
+var ErrInsufficientTreasury = errors.New("the treasury cannot cover this much liquidity")
+
+// seed puts perOutcome coins into every outcome pool of a new market, paid
+// for by userID or, for admins, by the treasury. The positions are settled
+// with the bets, so liquidity earns back its proportional share.
+func (s *MarketService) seed(tx *sql.Tx, marketID, userID uuid.UUID, perOutcome int, fromTreasury bool) error {
+	if perOutcome < 0 {
+		return ErrLiquidityAmount
+	}
+
+	if perOutcome == 0 {
+		return nil
+	}
+
+	outcomeIDs, err := s.OutcomeService.Outcomes.IDsForMarket(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	total := perOutcome * len(outcomeIDs)
+	funder := &userID
+
+	if fromTreasury {
+		isAdmin, err := s.UserService.Users.IsAdmin(userID)
+		if err != nil {
+			return err
+		}
+
+		if !isAdmin {
+			return ErrTreasuryNotAdmin
+		}
+
+		balance, err := s.Fees.TreasuryForUpdate(tx)
+		if err != nil {
+			return err
+		}
+
+		if balance < total {
+			return ErrInsufficientTreasury
+		}
+
+		funder = nil
+	} else {
+		user, err := s.UserService.Users.SelectForUpdate(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		if user.Balance < total {
+			return ErrInsufficientBalance
+		}
+
+		err = s.UserService.DecreaseBalanceBy(tx, userID, total)
+		if err != nil {
+			return err
+		}
+	}
+
+	seasonID, err := s.BetService.Seasons.ActiveID(tx)
+	if err != nil {
+		return err
+	}
+
+	for _, outcomeID := range outcomeIDs {
+		err = s.Liquidity.Insert(tx, marketID, outcomeID, funder, perOutcome, seasonID)
+		if err != nil {
+			return err
+		}
+
+		err = s.OutcomeService.AddPoolAmount(tx, outcomeID, perOutcome)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *MarketService) LiquidityPositions(marketID uuid.UUID) ([]models.LiquidityPosition, error) {
+	return s.Liquidity.ForMarket(marketID)
+}
@@ -1,7 +1,10 @@
 package services
 
 import (
+	"database/sql"
+	"errors"
 	"foresee/internal/models"
+	"foresee/internal/oracle"
 	"time"
 
 	"github.com/google/uuid"
@@ -9,64 +12,311 @@ import (
 
 type MarketService struct {
 	Markets        *models.MarketModel
+	Groups         *models.GroupModel
+	Revisions      *models.RevisionModel
+	Corrections    *models.CorrectionModel
+	Fees           *models.FeeModel
+	Integrity      *models.IntegrityModel
+	Disputes       *models.DisputeModel
+	Liquidity      *models.LiquidityModel
+	MarketEvents   *models.EventModel
+	Oracles        *models.OracleModel
+	OracleSources  oracle.Sources
 	BetService     BetService
 	OutcomeService OutcomeService
 	UserService    UserService
+	Events         *Events
 }
 
-func (s *MarketService) Create(
-	title string,
-	description string,
-	categoryStr string,
-	resolverTypeStr string,
-	expiresAtStr string,
-	userID uuid.UUID,
-) error {
-	category := models.Category(categoryStr)
-	resolverType := models.ResolverType(resolverTypeStr)
+// NewMarket carries the fields a user fills in when creating a market.
+// GroupID is nil for public markets.
+type NewMarket struct {
+	Title        string
+	Description  string
+	Category     string
+	ResolverType string
+	ExpiresAt    string
+	CreatedBy    uuid.UUID
+	GroupID      *uuid.UUID
+
+	// DisputeWindowHours delays settlement so bettors can dispute the
+	// resolver's answer. Zero settles immediately.
+	DisputeWindowHours int
+
+	// Liquidity is seeded into every outcome pool, paid by the creator or,
+	// when FromTreasury is set by an admin, by the treasury.
+	Liquidity    int
+	FromTreasury bool
+
+	// Kind defaults to a binary market. Scalar markets need both bounds.
+	Kind        string
+	ScalarLower *float64
+	ScalarUpper *float64
+	ScalarLog   bool
+
+	// Bucket markets split BucketLower to BucketUpper into steps of
+	// BucketSize. Date markets split BucketStart through BucketEnd, both
+	// formatted as DateLayout, into buckets of BucketDays.
+	BucketLower *float64
+	BucketUpper *float64
+	BucketSize  *float64
+	BucketStart string
+	BucketEnd   string
+	BucketDays  int
+
+	// ConditionOutcomeID makes the market conditional on that outcome of
+	// another market, see Condition.
+	ConditionOutcomeID *uuid.UUID
+
+	// SeriesID is set for markets created from a series, see SeriesService.
+	SeriesID *uuid.UUID
+
+	// EventID lists the market under an event. Binary markets of the event
+	// sharing a non-empty EventGroup are mutually exclusive.
+	EventID    *uuid.UUID
+	EventGroup string
+
+	// Oracle is the data source of markets with the oracle resolver type.
+	Oracle *oracle.Config
+
+	// ResolverUsername is the user asked to resolve a market with the
+	// designated resolver type, see AnswerDesignation.
+	ResolverUsername string
+}
 
-	loc, err := time.LoadLocation("Europe/Madrid")
+func (s *MarketService) Create(nm NewMarket) (uuid.UUID, error) {
+	tx, err := s.Markets.DB.Begin()
 	if err != nil {
-		return err
+		return uuid.UUID{}, err
 	}
 
-	expiresAt, err := time.ParseInLocation("2006-01-02T15:04", expiresAtStr, loc)
+	defer tx.Rollback()
+
+	id, err := s.create(tx, nm)
 	if err != nil {
-		return err
+		return uuid.UUID{}, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if models.ResolverType(nm.ResolverType) == models.ResolverDesignated {
+		err = s.requestResolver(id)
+		if err != nil {
+			return uuid.UUID{}, err
+		}
 	}
 
+	return id, nil
+}
+
+// create validates and inserts a market inside the caller's transaction.
+func (s *MarketService) create(tx *sql.Tx, nm NewMarket) (uuid.UUID, error) {
+	category := models.Category(nm.Category)
+	resolverType := models.ResolverType(nm.ResolverType)
+
+	kind := models.MarketKind(nm.Kind)
+	if kind == "" {
+		kind = models.KindBinary
+	}
+
+	var err error
+	var lower, upper *float64
+	var buckets bucketRange
+	switch kind {
+	case models.KindScalar:
+		err = checkScalarBounds(nm.ScalarLower, nm.ScalarUpper, nm.ScalarLog)
+		lower, upper = nm.ScalarLower, nm.ScalarUpper
+	case models.KindBucket:
+		buckets, err = numberBuckets(nm.BucketLower, nm.BucketUpper, nm.BucketSize)
+	case models.KindDate:
+		buckets, err = dateBuckets(nm.BucketStart, nm.BucketEnd, nm.BucketDays)
+	}
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	loc, err := marketLocation()
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	expiresAt, err := time.ParseInLocation("2006-01-02T15:04", nm.ExpiresAt, loc)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	suspended, err := s.UserService.Users.IsSuspended(nm.CreatedBy)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if suspended {
+		return uuid.UUID{}, ErrUserSuspended
+	}
+
+	if nm.GroupID != nil {
+		_, err = s.Groups.MemberRole(*nm.GroupID, nm.CreatedBy)
+		if err != nil {
+			if errors.Is(err, models.ErrNoRecord) {
+				return uuid.UUID{}, ErrNotGroupMember
+			}
+			return uuid.UUID{}, err
+		}
+	}
+
+	var conditionMarketID *uuid.UUID
+	if nm.ConditionOutcomeID != nil {
+		parent, _, err := s.Condition(*nm.ConditionOutcomeID, nm.CreatedBy)
+		if err != nil {
+			return uuid.UUID{}, err
+		}
+
+		if (parent.GroupID == nil) != (nm.GroupID == nil) || (parent.GroupID != nil && *parent.GroupID != *nm.GroupID) {
+			return uuid.UUID{}, ErrConditionGroup
+		}
+		conditionMarketID = &parent.ID
+	}
+
+	var eventGroup *string
+	if nm.EventID != nil {
+		_, err = s.MarketEvents.Get(*nm.EventID)
+		if err != nil {
+			if errors.Is(err, models.ErrNoRecord) {
+				return uuid.UUID{}, ErrEventNotFound
+			}
+			return uuid.UUID{}, err
+		}
+
+		if nm.EventGroup != "" {
+			if kind != models.KindBinary {
+				return uuid.UUID{}, ErrEventGroupKind
+			}
+			eventGroup = &nm.EventGroup
+		}
+	}
+
+	if resolverType == models.ResolverOracle {
+		if nm.Oracle == nil {
+			return uuid.UUID{}, ErrOracleRequired
+		}
+
+		err = s.OracleSources.Check(*nm.Oracle, kind == models.KindBinary)
+		if err != nil {
+			return uuid.UUID{}, err
+		}
+	}
+
+	fees, err := s.Fees.Schedule()
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	integrityRule, err := s.Integrity.Rule()
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	status := "open"
 	var resolverRef *uuid.UUID
-	if resolverType == models.ResolverCreator {
-		resolverRef = &userID
+	switch resolverType {
+	case models.ResolverCreator:
+		resolverRef = &nm.CreatedBy
+	case models.ResolverDesignated:
+		resolverID, err := s.designatedResolver(nm)
+		if err != nil {
+			return uuid.UUID{}, err
+		}
+		resolverRef = &resolverID
+		status = "pending"
 	}
 
-	tx, err := s.Markets.DB.Begin()
+	id, err := s.Markets.Insert(tx, models.Market{
+		Title:        nm.Title,
+		Description:  nm.Description,
+		Category:     category,
+		ResolverType: resolverType,
+		ResolverRef:  resolverRef,
+		ExpiresAt:    expiresAt,
+		Status:       status,
+		CreatedBy:    nm.CreatedBy,
+		GroupID:      nm.GroupID,
+
+		DisputeWindowHours: nm.DisputeWindowHours,
+		PlatformFeeBps:     fees.PlatformFeeBps,
+		CreatorFeeBps:      fees.CreatorFeeBps,
+		IntegrityRule:      integrityRule,
+
+		Kind:        kind,
+		ScalarLower: lower,
+		ScalarUpper: upper,
+		ScalarLog:   kind == models.KindScalar && nm.ScalarLog,
+		BucketLower: buckets.Lower,
+		BucketUpper: buckets.Upper,
+		BucketSize:  buckets.Size,
+
+		ConditionMarketID:  conditionMarketID,
+		ConditionOutcomeID: nm.ConditionOutcomeID,
+
+		SeriesID:   nm.SeriesID,
+		EventID:    nm.EventID,
+		EventGroup: eventGroup,
+	})
 	if err != nil {
-		return err
+		return uuid.UUID{}, err
 	}
 
-	defer tx.Rollback()
+	if kind.Bucketed() {
+		err = s.OutcomeService.Outcomes.CreateBuckets(tx, id, buckets.Buckets)
+	} else {
+		err = s.OutcomeService.CreateDefaultForMarket(tx, id, kind)
+	}
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if resolverType == models.ResolverOracle {
+		err = s.Oracles.Insert(tx, oracleRow(id, *nm.Oracle, kind))
+		if err != nil {
+			return uuid.UUID{}, err
+		}
+	}
 
-	id, err := s.Markets.Insert(
-		tx,
-		title,
-		description,
-		category,
-		resolverType,
-		resolverRef,
-		expiresAt,
-		userID,
-	)
+	err = s.seed(tx, id, nm.CreatedBy, nm.Liquidity, nm.FromTreasury)
 	if err != nil {
-		return err
+		return uuid.UUID{}, err
+	}
+
+	return id, nil
+}
+
+// CanAccess reports whether userID may see and bet on the market. Public
+// markets are open to everyone, including anonymous visitors (uuid.Nil),
+// until a moderator hides them.
+func (s *MarketService) CanAccess(m models.Market, userID uuid.UUID) (bool, error) {
+	if m.HiddenAt != nil {
+		return false, nil
+	}
+
+	if m.GroupID == nil {
+		return true, nil
 	}
 
-	err = s.OutcomeService.CreateDefaultForMarket(tx, id)
+	if userID == uuid.Nil {
+		return false, nil
+	}
+
+	_, err := s.Groups.MemberRole(*m.GroupID, userID)
 	if err != nil {
-		return err
+		if errors.Is(err, models.ErrNoRecord) {
+			return false, nil
+		}
+		return false, err
 	}
 
-	return tx.Commit()
+	return true, nil
 }
 
 func (s *MarketService) Get(id uuid.UUID) (models.Market, error) {
@@ -84,12 +334,25 @@ func (s *MarketService) Get(id uuid.UUID) (models.Market, error) {
 	return m, nil
 }
 
-func (s *MarketService) Latest() ([]*models.Market, error) {
-	markets, err := s.Markets.Latest()
+func (s *MarketService) Latest(viewerID uuid.UUID, eventID *uuid.UUID) ([]*models.Market, error) {
+	markets, err := s.Markets.Latest(viewerID, eventID)
 	if err != nil {
 		return nil, err
 	}
 
+	return s.withOutcomes(markets)
+}
+
+func (s *MarketService) ForGroup(groupID uuid.UUID) ([]*models.Market, error) {
+	markets, err := s.Markets.ForGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.withOutcomes(markets)
+}
+
+func (s *MarketService) withOutcomes(markets []*models.Market) ([]*models.Market, error) {
 	if len(markets) == 0 {
 		return markets, nil
 	}
@@ -112,10 +375,121 @@ func (s *MarketService) Latest() ([]*models.Market, error) {
 }
 
 func (s *MarketService) PendingResolution(userID uuid.UUID) ([]models.Market, error) {
-	return s.Markets.PendingResolution(userID)
+	isAdmin, err := s.UserService.Users.IsAdmin(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Markets.PendingResolution(userID, isAdmin)
 }
 
-func (s *MarketService) ResolveMarket(marketID uuid.UUID, userID uuid.UUID, outcomeID uuid.UUID) error {
+// Void cancels an open or pending market inside the caller's transaction
+// and refunds every stake, seeded liquidity included, then voids the
+// markets that depend on it. Stakes from archived seasons are
+// marked refunded but not credited, matching how ResolveMarket treats their
+// payouts.
+func (s *MarketService) Void(tx *sql.Tx, marketID uuid.UUID, userID uuid.UUID) error {
+	m, err := s.Markets.SelectForUpdate(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	if m.Status != "open" && m.Status != "pending" {
+		return models.ErrMarketAlreadyResolved
+	}
+
+	bets, err := s.BetService.ForMarketForUpdate(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range bets {
+		err = s.BetService.Bets.Refund(tx, b.ID)
+		if err != nil {
+			return err
+		}
+
+		if b.SeasonClosed {
+			continue
+		}
+
+		err = s.UserService.IncreaseBalanceBy(tx, b.UserID, b.Amount)
+		if err != nil {
+			return err
+		}
+	}
+
+	positions, err := s.Liquidity.ForMarketForUpdate(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range positions {
+		err = s.Liquidity.Refund(tx, p.ID)
+		if err != nil {
+			return err
+		}
+
+		if p.UserID == nil || p.SeasonClosed {
+			continue
+		}
+
+		err = s.UserService.IncreaseBalanceBy(tx, *p.UserID, p.Amount)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.Markets.Void(tx, marketID, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.voidConditions(tx, marketID, uuid.Nil, userID)
+}
+
+// CanResolve reports whether userID is the market's resolver. Admin
+// markets can be resolved by any admin, and so can oracle markets once
+// their oracle has given up, as in PendingResolution.
+func (s *MarketService) CanResolve(m models.Market, userID uuid.UUID) (bool, error) {
+	switch m.ResolverType {
+	case models.ResolverCreator, models.ResolverDesignated:
+		return m.ResolverRef != nil && *m.ResolverRef == userID, nil
+	case models.ResolverAdmin:
+		return s.UserService.Users.IsAdmin(userID)
+	case models.ResolverOracle:
+		o, err := s.Oracles.ForMarket(m.ID)
+		if err != nil {
+			if errors.Is(err, models.ErrNoRecord) {
+				return false, nil
+			}
+			return false, err
+		}
+
+		if o.FailedAt == nil {
+			return false, nil
+		}
+
+		return s.UserService.Users.IsAdmin(userID)
+	}
+
+	return false, nil
+}
+
+// ResolveMarket settles the market on the resolver's word, or proposes the
+// resolution when the market has a dispute window. Proposed resolutions are
+// settled by DisputeService once the window closes or an admin rules.
+//
+// shares maps outcomes to their part of the pool in basis points. Use
+// FullShare for a single winning outcome. Markets answered with a number are
+// resolved with ResolveValue instead.
+func (s *MarketService) ResolveMarket(marketID uuid.UUID, userID uuid.UUID, shares map[uuid.UUID]int) error {
+	return s.resolve(marketID, userID, shares, nil)
+}
+
+// resolve settles or proposes shares. value is the answer to a market
+// resolved by value and must be nil for any other kind.
+func (s *MarketService) resolve(marketID uuid.UUID, userID uuid.UUID, shares map[uuid.UUID]int, value *float64) error {
 	tx, err := s.Markets.DB.Begin()
 	if err != nil {
 		return err
@@ -128,15 +502,30 @@ func (s *MarketService) ResolveMarket(marketID uuid.UUID, userID uuid.UUID, outc
 		return err
 	}
 
-	if m.ResolverType != models.ResolverCreator {
-		return models.ErrUserNotAuthorized
+	allowed, err := s.CanResolve(m, userID)
+	if err != nil {
+		return err
 	}
 
-	if m.ResolverRef == nil || *m.ResolverRef != userID {
+	if !allowed {
 		return models.ErrUserNotAuthorized
 	}
 
-	if m.ResolvedOutcomeID != nil {
+	review, err := s.needsReview(m, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.resolveLocked(tx, m, userID, shares, value, review)
+}
+
+// resolveLocked carries on resolving m once tx has locked its row and the
+// resolver has been checked, committing tx. With review set the resolution
+// waits for an admin, see needsReview.
+func (s *MarketService) resolveLocked(tx *sql.Tx, m models.Market, userID uuid.UUID, shares map[uuid.UUID]int, value *float64, review bool) error {
+	marketID := m.ID
+
+	if m.ResolvedOutcomeID != nil || m.Status != "open" {
 		return models.ErrMarketAlreadyResolved
 	}
 
@@ -144,80 +533,75 @@ func (s *MarketService) ResolveMarket(marketID uuid.UUID, userID uuid.UUID, outc
 		return models.ErrMarketNotExpired
 	}
 
-	outcomeInMarket, err := s.OutcomeService.ExistsForMarketTx(tx, outcomeID, marketID)
+	err := s.checkCondition(m)
 	if err != nil {
 		return err
 	}
 
-	if !outcomeInMarket {
-		return models.ErrOutcomeDoesNotBelongToMarket
+	if m.Kind.ResolvedByValue() && value == nil {
+		return ErrValueRequired
 	}
 
-	bets, err := s.BetService.ForMarketForUpdate(tx, marketID)
+	if !m.Kind.ResolvedByValue() && value != nil {
+		return ErrNotValueMarket
+	}
+
+	err = s.checkShares(tx, marketID, shares)
 	if err != nil {
 		return err
 	}
 
-	totalPool := 0
-	winningPool := 0
-
-	for _, b := range bets {
-		totalPool += b.Amount
-		if b.OutcomeID == outcomeID {
-			winningPool += b.Amount
-		}
+	err = s.Markets.SetResolvedValue(tx, marketID, value)
+	if err != nil {
+		return err
 	}
 
-	distributed := 0
-	var firstWinner *models.Bet
-
-	for _, b := range bets {
-		if b.OutcomeID != outcomeID {
-			err = s.BetService.SetPayout(tx, b.ID, 0)
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		if winningPool == 0 {
-			err = s.BetService.SetPayout(tx, b.ID, 0)
-			if err != nil {
-				return err
-			}
-			continue
-		}
-
-		payout := b.Amount * totalPool / winningPool
-		distributed += payout
-
-		if firstWinner == nil {
-			firstWinner = &b
-		}
+	if review {
+		return s.review(tx, m, userID, shares)
+	}
 
-		err = s.BetService.SetPayout(tx, b.ID, payout)
+	if m.DisputeWindowHours > 0 {
+		err = s.OutcomeService.Outcomes.SetResolvedShares(tx, marketID, shares)
 		if err != nil {
 			return err
 		}
 
-		err = s.UserService.IncreaseBalanceBy(tx, b.UserID, payout)
+		finalizesAt := time.Now().Add(time.Duration(m.DisputeWindowHours) * time.Hour)
+		err = s.Markets.Propose(tx, marketID, userID, leadingOutcome(shares), finalizesAt)
 		if err != nil {
 			return err
 		}
+
+		return tx.Commit()
 	}
 
-	leftover := totalPool - distributed
-	if leftover > 0 && firstWinner != nil {
-		err = s.UserService.IncreaseBalanceBy(tx, firstWinner.UserID, leftover)
-		if err != nil {
-			return err
-		}
+	settled, err := s.Settle(tx, marketID, shares, userID)
+	if err != nil {
+		return err
 	}
 
-	err = s.Markets.ResolveMarket(tx, marketID, userID, outcomeID)
+	err = tx.Commit()
 	if err != nil {
 		return err
 	}
 
-	return tx.Commit()
+	s.Events.Publish(MarketResolved{
+		MarketID:   marketID,
+		OutcomeID:  leadingOutcome(shares),
+		ResolvedBy: manualResolver(m, userID),
+		Bets:       settled,
+	})
+
+	return nil
+}
+
+// manualResolver is who MarketResolved credits with resolving m: userID,
+// or nobody for oracle markets. Those are settled in their creator's name
+// even when the oracle answered on its own.
+func manualResolver(m models.Market, userID uuid.UUID) *uuid.UUID {
+	if m.ResolverType == models.ResolverOracle {
+		return nil
+	}
+
+	return &userID
 }
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"errors"
+	"foresee/internal/models"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrUserSuspended = errors.New("your account has been suspended")
+var ErrInvalidModerationAction = errors.New("that action cannot be applied to this item")
+
+type ModerationService struct {
+	Reports       *models.ReportModel
+	Log           *models.ModerationLogModel
+	Users         *models.UserModel
+	Comments      *models.CommentModel
+	MarketService *MarketService
+}
+
+// Report files a report against a market or comment the reporter can see
+// and returns the market it belongs to. Targets that do not exist or are
+// out of the reporter's sight return models.ErrNoRecord.
+func (s *ModerationService) Report(reporterID uuid.UUID, targetType models.ReportTarget, targetID uuid.UUID, reason models.ReportReason, details string) (uuid.UUID, error) {
+	marketID := targetID
+	if targetType == models.ReportComment {
+		c, err := s.Comments.Get(targetID)
+		if err != nil {
+			return uuid.UUID{}, err
+		}
+		marketID = c.MarketID
+	}
+
+	market, err := s.MarketService.Markets.Get(marketID)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	allowed, err := s.MarketService.CanAccess(market, reporterID)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if !allowed {
+		return uuid.UUID{}, models.ErrNoRecord
+	}
+
+	err = s.Reports.Insert(targetType, targetID, marketID, reporterID, reason, strings.TrimSpace(details))
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return marketID, nil
+}
+
+func (s *ModerationService) Queue() ([]models.ReportedItem, error) {
+	return s.Reports.Queue()
+}
+
+func (s *ModerationService) RecentActions(limit int) ([]models.ModerationLogEntry, error) {
+	return s.Log.Recent(limit)
+}
+
+// Act applies a moderator decision to a market or comment, closes the open
+// reports against it and writes the audit entry, all in one transaction.
+// Hiding a comment is also how moderators remove comments outside the queue.
+func (s *ModerationService) Act(moderatorID uuid.UUID, targetType models.ReportTarget, targetID uuid.UUID, action models.ModerationAction, note string) error {
+	role, err := s.Users.Role(moderatorID)
+	if err != nil {
+		return err
+	}
+
+	if !role.CanModerate() {
+		return models.ErrUserNotAuthorized
+	}
+
+	tx, err := s.Reports.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status := models.ReportActioned
+
+	switch action {
+	case models.ActionDismiss:
+		status = models.ReportDismissed
+
+	case models.ActionHide:
+		if targetType == models.ReportComment {
+			err = s.Comments.Hide(tx, targetID, moderatorID)
+		} else {
+			err = s.MarketService.Markets.Hide(tx, targetID)
+		}
+
+	case models.ActionVoid:
+		if targetType != models.ReportMarket {
+			return ErrInvalidModerationAction
+		}
+		err = s.MarketService.Void(tx, targetID, moderatorID)
+
+	case models.ActionSuspend:
+		var authorID uuid.UUID
+		authorID, err = s.Reports.ReportedAuthor(tx, targetType, targetID)
+		if err != nil {
+			return err
+		}
+
+		if authorID == moderatorID {
+			return ErrInvalidModerationAction
+		}
+		err = s.Users.Suspend(tx, authorID, note)
+
+	default:
+		return ErrInvalidModerationAction
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = s.Reports.Close(tx, targetType, targetID, status, moderatorID)
+	if err != nil {
+		return err
+	}
+
+	err = s.Log.Insert(tx, moderatorID, action, targetType, targetID, strings.TrimSpace(note))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"fmt"
+	"foresee/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// NotificationService turns events that need a user's attention into
+// notifications. Handle is subscribed to the Events bus.
+type NotificationService struct {
+	Notifications *models.NotificationModel
+	Users         *models.UserModel
+}
+
+func (s *NotificationService) Handle(ev Event) error {
+	switch e := ev.(type) {
+	case ResolverRequested:
+		creator, err := s.Users.Get(e.CreatedBy)
+		if err != nil {
+			return err
+		}
+
+		message := fmt.Sprintf("%s asked you to resolve %q. Betting opens once you accept.", creator.Username, e.MarketTitle)
+		return s.Notifications.Insert(e.ResolverID, models.NotificationResolverRequested, message, fmt.Sprintf("/markets/%s/resolver", e.MarketID))
+	case ResolverAnswered:
+		resolver, err := s.Users.Get(e.ResolverID)
+		if err != nil {
+			return err
+		}
+
+		if e.Accepted {
+			message := fmt.Sprintf("%s agreed to resolve %q, which is now open for bets.", resolver.Username, e.MarketTitle)
+			return s.Notifications.Insert(e.CreatedBy, models.NotificationResolverAccepted, message, fmt.Sprintf("/markets/%s", e.MarketID))
+		}
+
+		message := fmt.Sprintf("%s declined to resolve %q, so it was voided and your liquidity refunded.", resolver.Username, e.MarketTitle)
+		return s.Notifications.Insert(e.CreatedBy, models.NotificationResolverDeclined, message, fmt.Sprintf("/markets/%s", e.MarketID))
+	}
+
+	return nil
+}
+
+func (s *NotificationService) ForUser(userID uuid.UUID) ([]models.Notification, error) {
+	return s.Notifications.ForUser(userID)
+}
+
+func (s *NotificationService) Unread(userID uuid.UUID) (int, error) {
+	return s.Notifications.Unread(userID)
+}
+
+func (s *NotificationService) MarkAllRead(userID uuid.UUID) error {
+	return s.Notifications.MarkAllRead(userID)
+}
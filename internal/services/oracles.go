@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"foresee/internal/models"
+	"foresee/internal/oracle"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrOracleRequired = errors.New("markets resolved by an oracle need a data source")
+
+const (
+	// OracleAttempts is how many times an oracle reads its source before
+	// leaving the market to the admins, OracleRetryAfter apart.
+	OracleAttempts   = 3
+	OracleRetryAfter = 5 * time.Minute
+
+	oracleReadTimeout = 30 * time.Second
+)
+
+// OracleService resolves oracle markets once they expire, on behalf of the
+// creator who configured their data source.
+type OracleService struct {
+	Oracles       *models.OracleModel
+	MarketService *MarketService
+}
+
+func oracleRow(marketID uuid.UUID, cfg oracle.Config, kind models.MarketKind) models.Oracle {
+	row := models.Oracle{
+		MarketID: marketID,
+		Source:   string(cfg.Source),
+		Target:   cfg.Target,
+		Path:     cfg.Path,
+	}
+
+	// Markets answered with a value take the reading as it is.
+	if kind == models.KindBinary {
+		row.Operator = string(cfg.Operator)
+		row.Threshold = &cfg.Threshold
+	}
+
+	return row
+}
+
+func oracleConfig(o models.Oracle) oracle.Config {
+	cfg := oracle.Config{
+		Source:   oracle.Kind(o.Source),
+		Target:   o.Target,
+		Path:     o.Path,
+		Operator: oracle.Operator(o.Operator),
+	}
+	if o.Threshold != nil {
+		cfg.Threshold = *o.Threshold
+	}
+
+	return cfg
+}
+
+// ForMarket returns the oracle of an oracle market.
+func (s *OracleService) ForMarket(marketID uuid.UUID) (models.Oracle, error) {
+	return s.Oracles.ForMarket(marketID)
+}
+
+// Failed lists the open markets whose oracle gave up.
+func (s *OracleService) Failed() ([]models.Oracle, error) {
+	return s.Oracles.Failed()
+}
+
+// Retry has the oracle of a market it gave up on try again.
+func (s *OracleService) Retry(marketID uuid.UUID) error {
+	return s.Oracles.Retry(marketID)
+}
+
+// ResolveDue reads the source of every expired oracle market and resolves
+// it to the answer. A source that cannot be read, or a reading that answers
+// nothing, counts as a failed attempt. One market failing does not hold up
+// the others; their errors are returned together. It runs as a background
+// job.
+func (s *OracleService) ResolveDue() error {
+	due, err := s.Oracles.Due(OracleRetryAfter)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, o := range due {
+		err = s.resolve(o)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("market %s: %w", o.MarketID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *OracleService) resolve(o models.Oracle) error {
+	m, err := s.MarketService.Get(o.MarketID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), oracleReadTimeout)
+	defer cancel()
+
+	value, err := s.MarketService.OracleSources.Read(ctx, oracleConfig(o))
+	if err != nil {
+		return s.Oracles.RecordFailure(o.MarketID, err.Error(), OracleAttempts)
+	}
+
+	shares, answer, err := oracleShares(m, oracleConfig(o), value)
+	if err != nil {
+		// The source answered, so asking it again would not help.
+		return s.Oracles.RecordFailure(o.MarketID, err.Error(), 1)
+	}
+
+	err = s.MarketService.resolveOracle(o.MarketID, shares, answer)
+	switch {
+	case errors.Is(err, models.ErrMarketAlreadyResolved):
+		// An admin got there first. The reading is kept for the record.
+		return s.Oracles.RecordUnused(o.MarketID, value, "the market was resolved or voided before the reading could be used")
+	case errors.Is(err, ErrConditionPending):
+		// The market it depends on was reopened since it was listed. Due
+		// picks it up again once that market resolves.
+		return s.Oracles.RecordUnused(o.MarketID, value, "the market it depends on has not resolved yet")
+	case errors.Is(err, ErrConditionNotMet):
+		return s.Oracles.RecordUnused(o.MarketID, value, "the market it depends on did not resolve to its outcome")
+	case err != nil:
+		// Count the attempt so a market that cannot be resolved is not
+		// retried on every run, and eventually reaches the admins.
+		return errors.Join(err, s.Oracles.RecordFailure(o.MarketID, err.Error(), OracleAttempts))
+	}
+
+	return s.Oracles.RecordValue(o.MarketID, value)
+}
+
+// oracleShares maps a reading to the resolution of m: yes or no by the
+// comparison for binary markets, and the value itself for the others, in
+// which case it is returned as the answer to record.
+func oracleShares(m models.Market, cfg oracle.Config, value float64) (map[uuid.UUID]int, *float64, error) {
+	if m.Kind.ResolvedByValue() {
+		shares, err := ValueShares(m, value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%v: %w", value, err)
+		}
+		return shares, &value, nil
+	}
+
+	label := models.NoLabel
+	if cfg.Operator.Holds(value, cfg.Threshold) {
+		label = models.YesLabel
+	}
+
+	for _, o := range m.Outcomes {
+		if o.Label == label {
+			return FullShare(o.ID), nil, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("the market has no %s outcome", label)
+}
+
+// resolveOracle resolves an oracle market like resolve does, in the name of
+// its creator.
+func (s *MarketService) resolveOracle(marketID uuid.UUID, shares map[uuid.UUID]int, value *float64) error {
+	tx, err := s.Markets.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	m, err := s.Markets.SelectForUpdate(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	if m.ResolverType != models.ResolverOracle {
+		return models.ErrUserNotAuthorized
+	}
+
+	return s.resolveLocked(tx, m, m.CreatedBy, shares, value, false)
+}
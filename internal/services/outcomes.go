@@ -11,7 +11,13 @@ type OutcomeService struct {
 	Outcomes *models.OutcomeModel
 }
 
-func (s *OutcomeService) CreateDefaultForMarket(tx *sql.Tx, marketID uuid.UUID) error {
+// CreateDefaultForMarket adds the fixed outcomes of a market of the given
+// kind: yes and no, or long and short for scalar markets.
+func (s *OutcomeService) CreateDefaultForMarket(tx *sql.Tx, marketID uuid.UUID, kind models.MarketKind) error {
+	if kind == models.KindScalar {
+		return s.Outcomes.CreateWithLongShort(tx, marketID)
+	}
+
 	return s.Outcomes.CreateWithYesNo(tx, marketID)
 }
 
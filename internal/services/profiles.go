@@ -0,0 +1,156 @@
+package services
+
+import (
+	"fmt"
+	"foresee/internal/models"
+)
+
+type ProfileService struct {
+	Users        *models.UserModel
+	Markets      *models.MarketModel
+	Bets         *models.BetModel
+	Seasons      *models.SeasonModel
+	Achievements *AchievementService
+}
+
+type Profile struct {
+	User            models.User
+	CreatedMarkets  []models.Market
+	ResolvedMarkets []models.Market
+	Bets            []models.BetHistoryRow
+	Record          TrackRecord
+	Seasons         []models.SeasonStanding
+	Achievements    []Achievement
+}
+
+type CalibrationBucket struct {
+	Label        string
+	Bets         int
+	AvgPredicted float64
+	HitRate      float64
+}
+
+type TrackRecord struct {
+	Settled     int
+	Wins        int
+	WinRate     float64
+	Staked      int
+	NetProfit   int
+	Calibration []CalibrationBucket
+}
+
+const calibrationBuckets = 5
+
+func (s *ProfileService) Get(username string) (Profile, error) {
+	user, err := s.Users.GetByUsername(username)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	created, err := s.Markets.CreatedByUser(user.ID)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	resolved, err := s.Markets.ResolvedByUser(user.ID)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	history, err := s.Bets.GetUserBetHistory(user.ID)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	seasons, err := s.Seasons.StandingsForUser(user.ID)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	achievements, err := s.Achievements.ForUser(user.ID)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	p := Profile{
+		User:            user,
+		CreatedMarkets:  created,
+		ResolvedMarkets: resolved,
+		Record:          NewTrackRecord(history),
+		Seasons:         seasons,
+		Achievements:    achievements,
+	}
+
+	// Bets on group markets stay private even when the user shares their
+	// history, otherwise the profile would leak the group's market titles.
+	if user.PublicBets {
+		for _, b := range history {
+			if b.MarketGroupID == nil {
+				p.Bets = append(p.Bets, b)
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// NewTrackRecord summarises settled bets. Calibration groups bets by the
+// implied probability at bet time and compares it with how often they won.
+func NewTrackRecord(rows []models.BetHistoryRow) TrackRecord {
+	var r TrackRecord
+
+	type acc struct {
+		bets      int
+		wins      int
+		predicted float64
+	}
+	buckets := make([]acc, calibrationBuckets)
+
+	for _, row := range rows {
+		if row.Result == "pending" || row.Payout == nil {
+			continue
+		}
+
+		won := row.Result == "win"
+
+		r.Settled++
+		r.Staked += row.Amount
+		r.NetProfit += *row.Payout - row.Amount
+		if won {
+			r.Wins++
+		}
+
+		if row.ImpliedProbability == nil {
+			continue
+		}
+
+		p := *row.ImpliedProbability
+		i := min(int(p*calibrationBuckets), calibrationBuckets-1)
+		buckets[i].bets++
+		buckets[i].predicted += p
+		if won {
+			buckets[i].wins++
+		}
+	}
+
+	if r.Settled > 0 {
+		r.WinRate = float64(r.Wins) / float64(r.Settled)
+	}
+
+	width := 100 / calibrationBuckets
+	for i, b := range buckets {
+		bucket := CalibrationBucket{
+			Label: fmt.Sprintf("%d–%d%%", i*width, (i+1)*width),
+			Bets:  b.bets,
+		}
+
+		if b.bets > 0 {
+			bucket.AvgPredicted = b.predicted / float64(b.bets)
+			bucket.HitRate = float64(b.wins) / float64(b.bets)
+		}
+
+		r.Calibration = append(r.Calibration, bucket)
+	}
+
+	return r
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"errors"
+	"foresee/internal/models"
+	"foresee/internal/payout"
+
+	"github.com/google/uuid"
+)
+
+var ErrQuoteAmount = errors.New("the amount must be a positive number of coins")
+var ErrQuoteTooLarge = errors.New("the amount is larger than any bet can be")
+
+// MaxQuoteAmount caps the amounts the bet preview prices. Nobody holds
+// anywhere near this many coins, and keeping amounts this small leaves the
+// pool arithmetic far from overflowing.
+const MaxQuoteAmount = 1_000_000_000
+
+// Quote is what a new bet would be paid if its outcome won outright, given
+// the bets placed so far.
+type Quote struct {
+	OutcomeID          uuid.UUID `json:"outcome_id"`
+	Amount             int       `json:"amount"`
+	Payout             int       `json:"payout"`
+	Profit             int       `json:"profit"`
+	Odds               float64   `json:"odds"`
+	ImpliedProbability float64   `json:"implied_probability"`
+}
+
+// Quote prices a hypothetical bet of amount on outcomeID. The bet is settled
+// as the latest stake with payout.Compute, so the figure matches what
+// resolution would pay, fees and rounding included, if nobody else bets.
+func (s *MarketService) Quote(m models.Market, outcomeID uuid.UUID, amount int) (Quote, error) {
+	if amount <= 0 {
+		return Quote{}, ErrQuoteAmount
+	}
+
+	if amount > MaxQuoteAmount {
+		return Quote{}, ErrQuoteTooLarge
+	}
+
+	totalPool, outcomePool, found := 0, 0, false
+	for _, o := range m.Outcomes {
+		totalPool += o.PoolAmount
+		if o.ID == outcomeID {
+			outcomePool, found = o.PoolAmount, true
+		}
+	}
+
+	if !found {
+		return Quote{}, models.ErrOutcomeDoesNotBelongToMarket
+	}
+
+	positions, bets, err := s.positions(m.ID)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	plan, err := payout.Compute(append(stakes(positions, bets), payout.Stake{OutcomeID: outcomeID, Amount: amount}), FullShare(outcomeID), marketFees(m))
+	if err != nil {
+		if errors.Is(err, payout.ErrPoolOverflow) {
+			return Quote{}, ErrQuoteTooLarge
+		}
+		return Quote{}, err
+	}
+
+	paid := plan.Lines[len(plan.Lines)-1].Payout()
+
+	return Quote{
+		OutcomeID:          outcomeID,
+		Amount:             amount,
+		Payout:             paid,
+		Profit:             paid - amount,
+		Odds:               float64(paid) / float64(amount),
+		ImpliedProbability: ImpliedProbability(outcomePool, totalPool, amount),
+	}, nil
+}
+
+// PreviewOutcomes plans the payouts of resolving the market entirely to each
+// of its outcomes in turn, keyed by outcome.
+func (s *MarketService) PreviewOutcomes(m models.Market) (map[uuid.UUID]PayoutPreview, error) {
+	positions, bets, err := s.positions(m.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	previews := make(map[uuid.UUID]PayoutPreview, len(m.Outcomes))
+	for _, o := range m.Outcomes {
+		plan, err := payout.Compute(stakes(positions, bets), FullShare(o.ID), marketFees(m))
+		if err != nil {
+			return nil, err
+		}
+
+		previews[o.ID] = PayoutPreview{
+			Plan:      plan,
+			Liquidity: positions,
+			Bets:      bets,
+		}
+	}
+
+	return previews, nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"errors"
+	"foresee/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrResolverNotFound = errors.New("there is no user with that username")
+var ErrResolverSelf = errors.New("pick someone other than yourself, or make yourself the resolver")
+var ErrResolverNotMember = errors.New("the resolver must be a member of the market's group")
+var ErrResolverSuspended = errors.New("that user's account has been suspended")
+var ErrResolverCannotBet = errors.New("you cannot bet on a market you resolve")
+var ErrDesignationAnswered = errors.New("this request has already been answered")
+var ErrDesignationExpired = errors.New("the market expired before the request was accepted")
+
+// designatedResolver looks up the user nm asks to resolve the market, who
+// must be able to see it.
+func (s *MarketService) designatedResolver(nm NewMarket) (uuid.UUID, error) {
+	user, err := s.UserService.Users.GetByUsername(nm.ResolverUsername)
+	if err != nil {
+		if errors.Is(err, models.ErrNoRecord) {
+			return uuid.UUID{}, ErrResolverNotFound
+		}
+		return uuid.UUID{}, err
+	}
+
+	if user.ID == nm.CreatedBy {
+		return uuid.UUID{}, ErrResolverSelf
+	}
+
+	suspended, err := s.UserService.Users.IsSuspended(user.ID)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if suspended {
+		return uuid.UUID{}, ErrResolverSuspended
+	}
+
+	if nm.GroupID != nil {
+		_, err = s.Groups.MemberRole(*nm.GroupID, user.ID)
+		if err != nil {
+			if errors.Is(err, models.ErrNoRecord) {
+				return uuid.UUID{}, ErrResolverNotMember
+			}
+			return uuid.UUID{}, err
+		}
+	}
+
+	return user.ID, nil
+}
+
+func (s *MarketService) requestResolver(marketID uuid.UUID) error {
+	m, err := s.Markets.Get(marketID)
+	if err != nil {
+		return err
+	}
+
+	s.Events.Publish(ResolverRequested{
+		MarketID:    m.ID,
+		MarketTitle: m.Title,
+		CreatedBy:   m.CreatedBy,
+		ResolverID:  *m.ResolverRef,
+	})
+
+	return nil
+}
+
+// IsDesignatedResolver reports whether userID was asked to resolve m.
+func IsDesignatedResolver(m models.Market, userID uuid.UUID) bool {
+	return m.ResolverType == models.ResolverDesignated && m.ResolverRef != nil && *m.ResolverRef == userID
+}
+
+// AnswerDesignation records the designated resolver's answer. Accepting
+// opens the market for betting. Declining voids it, refunding the
+// creator's liquidity, and the creator is left to ask someone else in a
+// new market.
+func (s *MarketService) AnswerDesignation(marketID, userID uuid.UUID, accept bool) error {
+	tx, err := s.Markets.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	m, err := s.Markets.SelectForUpdate(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	if !IsDesignatedResolver(m, userID) {
+		return models.ErrUserNotAuthorized
+	}
+
+	if m.Status != "pending" {
+		return ErrDesignationAnswered
+	}
+
+	if accept {
+		if time.Now().After(m.ExpiresAt) {
+			return ErrDesignationExpired
+		}
+
+		// Nothing left to resolve if the market it depends on went the
+		// other way.
+		err = s.checkCondition(m)
+		if err != nil && !errors.Is(err, ErrConditionPending) {
+			return err
+		}
+
+		err = s.Markets.Open(tx, marketID)
+	} else {
+		// Voided in the creator's name, so the market is not counted among
+		// those the declining user resolved.
+		err = s.Void(tx, marketID, m.CreatedBy)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	s.Events.Publish(ResolverAnswered{
+		MarketID:    m.ID,
+		MarketTitle: m.Title,
+		CreatedBy:   m.CreatedBy,
+		ResolverID:  userID,
+		Accepted:    accept,
+	})
+
+	return nil
+}
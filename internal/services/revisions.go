@@ -0,0 +1,204 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"foresee/internal/models"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrMarketHasBets = errors.New("this market already has bets, only clarifications can be added")
+var ErrMarketNotEditable = errors.New("only open markets can be edited")
+var ErrClarificationEmpty = errors.New("the clarification cannot be empty")
+
+// MarketEdit holds the editable terms of a market as submitted by the form.
+type MarketEdit struct {
+	Title       string
+	Description string
+	Category    string
+	ExpiresAt   string
+}
+
+type RevisionGroup struct {
+	Revision       int
+	EditorUsername string
+	CreatedAt      time.Time
+	Changes        []models.MarketRevision
+}
+
+const revisionTimeLayout = "2006-01-02 15:04"
+
+// Edit changes the terms of a market that nobody has bet on yet. Every
+// changed field is stored as part of one revision.
+func (s *MarketService) Edit(marketID, userID uuid.UUID, edit MarketEdit) error {
+	loc, err := marketLocation()
+	if err != nil {
+		return err
+	}
+
+	expiresAt, err := time.ParseInLocation("2006-01-02T15:04", edit.ExpiresAt, loc)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.Markets.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	m, err := s.editableMarket(tx, marketID, userID)
+	if err != nil {
+		return err
+	}
+
+	bets, err := s.BetService.Bets.CountForMarket(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	if bets > 0 {
+		return ErrMarketHasBets
+	}
+
+	revision, err := s.Revisions.NextRevision(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	changes := []struct {
+		field    models.RevisionField
+		old, new string
+	}{
+		{models.RevisionTitle, m.Title, edit.Title},
+		{models.RevisionDescription, m.Description, edit.Description},
+		{models.RevisionCategory, string(m.Category), edit.Category},
+		{models.RevisionExpiresAt, m.ExpiresAt.In(loc).Format(revisionTimeLayout), expiresAt.Format(revisionTimeLayout)},
+	}
+
+	changed := false
+	for _, c := range changes {
+		if c.old == c.new {
+			continue
+		}
+		changed = true
+
+		old := c.old
+		err = s.Revisions.Insert(tx, models.MarketRevision{
+			MarketID: marketID,
+			Revision: revision,
+			EditedBy: userID,
+			Field:    c.field,
+			OldValue: &old,
+			NewValue: c.new,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	m.Title = edit.Title
+	m.Description = edit.Description
+	m.Category = models.Category(edit.Category)
+	m.ExpiresAt = expiresAt
+
+	err = s.Markets.Update(tx, m)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Clarify appends a dated note to the description. It is the only change
+// allowed once bets exist, so earlier wording is never lost.
+func (s *MarketService) Clarify(marketID, userID uuid.UUID, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ErrClarificationEmpty
+	}
+
+	tx, err := s.Markets.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	m, err := s.editableMarket(tx, marketID, userID)
+	if err != nil {
+		return err
+	}
+
+	revision, err := s.Revisions.NextRevision(tx, marketID)
+	if err != nil {
+		return err
+	}
+
+	err = s.Revisions.Insert(tx, models.MarketRevision{
+		MarketID: marketID,
+		Revision: revision,
+		EditedBy: userID,
+		Field:    models.RevisionClarification,
+		NewValue: text,
+	})
+	if err != nil {
+		return err
+	}
+
+	m.Description = fmt.Sprintf("%s\n\nClarification (%s): %s", m.Description, time.Now().UTC().Format("2006-01-02"), text)
+
+	err = s.Markets.Update(tx, m)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *MarketService) editableMarket(tx *sql.Tx, marketID, userID uuid.UUID) (models.Market, error) {
+	m, err := s.Markets.SelectForUpdate(tx, marketID)
+	if err != nil {
+		return models.Market{}, err
+	}
+
+	if m.CreatedBy != userID {
+		return models.Market{}, models.ErrUserNotAuthorized
+	}
+
+	if m.Status != "open" {
+		return models.Market{}, ErrMarketNotEditable
+	}
+
+	return m, nil
+}
+
+// History groups the market's revisions, newest first.
+func (s *MarketService) History(marketID uuid.UUID) ([]RevisionGroup, error) {
+	rows, err := s.Revisions.ForMarket(marketID)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []RevisionGroup
+	for _, r := range rows {
+		if len(groups) == 0 || groups[len(groups)-1].Revision != r.Revision {
+			groups = append(groups, RevisionGroup{
+				Revision:       r.Revision,
+				EditorUsername: r.EditorUsername,
+				CreatedAt:      r.CreatedAt,
+			})
+		}
+		last := &groups[len(groups)-1]
+		last.Changes = append(last.Changes, r)
+	}
+
+	return groups, nil
+}
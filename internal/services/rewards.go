@@ -0,0 +1,79 @@
+package services
+
+import (
+	"database/sql"
+	"foresee/internal/models"
+	"time"
+)
+
+const defaultTimezone = "Europe/Madrid"
+
+func userLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		loc, _ = time.LoadLocation(defaultTimezone)
+	}
+
+	return loc
+}
+
+// ClaimDay is the calendar day of now in the user's timezone, expressed as
+// midnight UTC so that it compares cleanly with DATE columns.
+func ClaimDay(now time.Time, loc *time.Location) time.Time {
+	y, m, d := now.In(loc).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// daysBetween counts the calendar days from one claim day to another. Only
+// their dates are compared, whatever location or time of day they carry.
+func daysBetween(from, to time.Time) int {
+	fy, fm, fd := from.Date()
+	ty, tm, td := to.Date()
+
+	start := time.Date(fy, fm, fd, 0, 0, 0, 0, time.UTC)
+	end := time.Date(ty, tm, td, 0, 0, 0, 0, time.UTC)
+
+	return int(end.Sub(start) / (24 * time.Hour))
+}
+
+func CanClaimReward(today time.Time, lastClaimedAt sql.NullTime) bool {
+	if !lastClaimedAt.Valid {
+		return true
+	}
+
+	return daysBetween(lastClaimedAt.Time, today) >= 1
+}
+
+// NextStreak is the streak a claim made today would reach. Missing up to
+// GraceDays calendar days keeps the streak alive; missing more restarts it.
+func NextStreak(rs models.RewardSchedule, today time.Time, lastClaimedAt sql.NullTime, streak int) int {
+	if !lastClaimedAt.Valid {
+		return 1
+	}
+
+	if daysBetween(lastClaimedAt.Time, today) > 1+rs.GraceDays {
+		return 1
+	}
+
+	return streak + 1
+}
+
+// CurrentStreak is the streak to display today: the stored streak while it
+// can still be continued, zero once it has lapsed.
+func CurrentStreak(rs models.RewardSchedule, today time.Time, lastClaimedAt sql.NullTime, streak int) int {
+	if !lastClaimedAt.Valid || daysBetween(lastClaimedAt.Time, today) > 1+rs.GraceDays {
+		return 0
+	}
+
+	return streak
+}
+
+func RewardAmount(rs models.RewardSchedule, streak int) int {
+	amount := rs.BaseAmount + rs.BaseAmount*rs.StreakStepBps*max(streak-1, 0)/10000
+
+	if rs.MaxAmount > 0 {
+		amount = min(amount, rs.MaxAmount)
+	}
+
+	return amount
+}
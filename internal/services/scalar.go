@@ -0,0 +1,69 @@
+package services
+
+import (
+	"errors"
+	"foresee/internal/models"
+	"foresee/internal/payout"
+	"math"
+
+	"github.com/google/uuid"
+)
+
+var ErrScalarBounds = errors.New("the upper bound must be above the lower bound, and both must be positive on a log scale")
+
+// checkScalarBounds validates the range of a new scalar market.
+func checkScalarBounds(lower, upper *float64, log bool) error {
+	if lower == nil || upper == nil {
+		return ErrScalarBounds
+	}
+
+	for _, b := range []float64{*lower, *upper} {
+		if math.IsNaN(b) || math.IsInf(b, 0) {
+			return ErrScalarBounds
+		}
+	}
+
+	if *lower >= *upper || (log && *lower <= 0) {
+		return ErrScalarBounds
+	}
+
+	return nil
+}
+
+// ScalarShares splits a scalar market's pool between its long and short
+// outcomes for an answer of value, see payout.ScalarShare. m must have its
+// outcomes loaded.
+func ScalarShares(m models.Market, value float64) (map[uuid.UUID]int, error) {
+	if m.Kind != models.KindScalar || m.ScalarLower == nil || m.ScalarUpper == nil {
+		return nil, ErrNotValueMarket
+	}
+
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil, ErrInvalidValue
+	}
+
+	long := payout.ScalarShare(value, *m.ScalarLower, *m.ScalarUpper, m.ScalarLog)
+
+	shares := make(map[uuid.UUID]int, 2)
+	for _, o := range m.Outcomes {
+		switch o.Label {
+		case models.LongLabel:
+			shares[o.ID] = long
+		case models.ShortLabel:
+			shares[o.ID] = payout.FullShare - long
+		}
+	}
+
+	if len(shares) != 2 {
+		return nil, ErrNotValueMarket
+	}
+
+	// Leave out the side that gets nothing, as a split resolution would.
+	for id, bps := range shares {
+		if bps == 0 {
+			delete(shares, id)
+		}
+	}
+
+	return shares, nil
+}
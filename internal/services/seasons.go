@@ -0,0 +1,114 @@
+package services
+
+import (
+	"errors"
+	"foresee/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrSeasonOverlaps = errors.New("the season overlaps with an existing season")
+
+const seasonStandingsSize = 50
+
+// SeasonService owns the season lifecycle. A season resets every balance to
+// its starting amount when it opens and again when it closes, after its
+// standings have been archived.
+//
+// Markets that are still open when a season ends are handled as follows:
+// stakes placed during the closing season are valued at cost in the archived
+// standings, and when such a market later settles the payout is recorded on
+// the bet but not credited, because those coins were already counted in the
+// old season. Bets placed in the new season settle normally against the
+// same pool.
+type SeasonService struct {
+	Seasons *models.SeasonModel
+	Users   *models.UserModel
+}
+
+func (s *SeasonService) Create(name string, startsAt, endsAt time.Time, startingBalance int, adminID uuid.UUID) error {
+	overlaps, err := s.Seasons.Overlaps(startsAt, endsAt)
+	if err != nil {
+		return err
+	}
+
+	if overlaps {
+		return ErrSeasonOverlaps
+	}
+
+	return s.Seasons.Insert(name, startsAt, endsAt, startingBalance, adminID)
+}
+
+func (s *SeasonService) All() ([]models.Season, error) {
+	return s.Seasons.All()
+}
+
+func (s *SeasonService) Archived() ([]models.Season, error) {
+	return s.Seasons.Archived()
+}
+
+func (s *SeasonService) Standings(seasonID uuid.UUID) ([]models.SeasonStanding, error) {
+	return s.Seasons.Standings(seasonID, seasonStandingsSize)
+}
+
+func (s *SeasonService) StandingsForUser(userID uuid.UUID) ([]models.SeasonStanding, error) {
+	return s.Seasons.StandingsForUser(userID)
+}
+
+// Advance opens and closes every season that is due, one transaction per
+// transition, until nothing is left to do.
+func (s *SeasonService) Advance() error {
+	for {
+		done, err := s.advanceOne(time.Now())
+		if err != nil {
+			return err
+		}
+
+		if done {
+			return nil
+		}
+	}
+}
+
+func (s *SeasonService) advanceOne(now time.Time) (bool, error) {
+	tx, err := s.Seasons.DB.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	season, err := s.Seasons.Due(tx, now)
+	if err != nil {
+		if errors.Is(err, models.ErrNoRecord) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	switch season.Status {
+	case models.SeasonScheduled:
+		err = s.Seasons.SetStatus(tx, season.ID, models.SeasonActive)
+		if err != nil {
+			return false, err
+		}
+
+	case models.SeasonActive:
+		err = s.Seasons.ArchiveStandings(tx, season.ID)
+		if err != nil {
+			return false, err
+		}
+
+		err = s.Seasons.SetStatus(tx, season.ID, models.SeasonArchived)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	err = s.Users.ResetBalances(tx, season.StartingBalance)
+	if err != nil {
+		return false, err
+	}
+
+	return false, tx.Commit()
+}
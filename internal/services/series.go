@@ -0,0 +1,285 @@
+package services
+
+import (
+	"errors"
+	"foresee/internal/models"
+	"foresee/internal/recurrence"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrSeriesKind = errors.New("only yes/no markets outside of events that do not depend on another market can repeat")
+var ErrSeriesResolver = errors.New("only markets resolved by their creator or an admin can repeat")
+var ErrSeriesPermission = errors.New("only the creator of a series or an admin can stop it")
+
+// seriesBatch caps how many markets one run of CreateDue creates. Series
+// left over are the next run's most overdue.
+const seriesBatch = 50
+
+type SeriesService struct {
+	Series        *models.SeriesModel
+	MarketService *MarketService
+}
+
+// NewSeries is a market template, whose title and description may use the
+// placeholders described at expandTemplate, and the rule its markets expire
+// on. NewMarket.ExpiresAt is ignored.
+type NewSeries struct {
+	NewMarket
+	Rule string
+}
+
+type SeriesPage struct {
+	models.Series
+	Schedule string
+	Markets  []*models.Market
+	Stats    models.SeriesStats
+	CanStop  bool
+}
+
+// Create saves the series and its first market, which expires at the next
+// occurrence of the rule.
+func (s *SeriesService) Create(ns NewSeries) (uuid.UUID, error) {
+	rule, err := recurrence.Parse(ns.Rule)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	if (ns.Kind != "" && ns.Kind != string(models.KindBinary)) || ns.ConditionOutcomeID != nil || ns.EventID != nil {
+		return uuid.UUID{}, ErrSeriesKind
+	}
+
+	if ns.ResolverType == string(models.ResolverOracle) || ns.ResolverType == string(models.ResolverDesignated) {
+		return uuid.UUID{}, ErrSeriesResolver
+	}
+
+	loc, err := marketLocation()
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	expiresAt := rule.Next(time.Now().In(loc))
+
+	tx, err := s.Series.DB.Begin()
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	defer tx.Rollback()
+
+	series := models.Series{
+		Title:              ns.Title,
+		Description:        ns.Description,
+		Category:           models.Category(ns.Category),
+		ResolverType:       models.ResolverType(ns.ResolverType),
+		GroupID:            ns.GroupID,
+		DisputeWindowHours: ns.DisputeWindowHours,
+		Liquidity:          ns.Liquidity,
+		Rule:               rule.String(),
+		CreatedBy:          ns.CreatedBy,
+	}
+
+	series.ID, err = s.Series.Insert(tx, series)
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	_, err = s.MarketService.create(tx, s.instance(series, expiresAt))
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	err = s.Series.Advance(tx, series.ID, rule.Next(expiresAt))
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return series.ID, tx.Commit()
+}
+
+// CreateDue creates the next market of running series whose latest market
+// has expired, at most one per series and seriesBatch in all. Series whose
+// creator can no longer pay for or post their markets are stopped rather
+// than retried every run.
+func (s *SeriesService) CreateDue() error {
+	due, err := s.Series.Due(seriesBatch)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, series := range due {
+		err = s.createNext(series)
+		if errors.Is(err, ErrInsufficientBalance) || errors.Is(err, ErrUserSuspended) || errors.Is(err, ErrNotGroupMember) ||
+			errors.Is(err, recurrence.ErrNeverOccurs) || errors.Is(err, recurrence.ErrTooFrequent) {
+			err = s.Series.Stop(series.ID, err.Error())
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *SeriesService) createNext(series models.Series) error {
+	rule, err := recurrence.Parse(series.Rule)
+	if err != nil {
+		return err
+	}
+
+	loc, err := marketLocation()
+	if err != nil {
+		return err
+	}
+
+	// Skip the occurrences missed while the scheduler was not running.
+	now := time.Now()
+	expiresAt := series.NextExpiresAt.In(loc)
+	for !expiresAt.After(now) {
+		expiresAt = rule.Next(expiresAt)
+		if expiresAt.IsZero() {
+			return recurrence.ErrNeverOccurs
+		}
+	}
+
+	tx, err := s.Series.DB.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	_, err = s.MarketService.create(tx, s.instance(series, expiresAt))
+	if err != nil {
+		return err
+	}
+
+	err = s.Series.Advance(tx, series.ID, rule.Next(expiresAt))
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// instance fills in the series template for the market expiring at
+// expiresAt.
+func (s *SeriesService) instance(series models.Series, expiresAt time.Time) NewMarket {
+	n := series.Instances + 1
+
+	return NewMarket{
+		Title:        expandTemplate(series.Title, n, expiresAt),
+		Description:  expandTemplate(series.Description, n, expiresAt),
+		Category:     string(series.Category),
+		ResolverType: string(series.ResolverType),
+		ExpiresAt:    expiresAt.Format("2006-01-02T15:04"),
+		CreatedBy:    series.CreatedBy,
+		GroupID:      series.GroupID,
+
+		DisputeWindowHours: series.DisputeWindowHours,
+		Liquidity:          series.Liquidity,
+
+		SeriesID: &series.ID,
+	}
+}
+
+// expandTemplate replaces the placeholders of a series template: {n} is
+// the market's number in the series, and {date}, {weekday}, {week},
+// {month} and {year} describe when it expires.
+func expandTemplate(text string, n int, expiresAt time.Time) string {
+	_, week := expiresAt.ISOWeek()
+
+	return strings.NewReplacer(
+		"{n}", strconv.Itoa(n),
+		"{date}", expiresAt.Format(DateLayout),
+		"{weekday}", expiresAt.Weekday().String(),
+		"{week}", strconv.Itoa(week),
+		"{month}", expiresAt.Month().String(),
+		"{year}", strconv.Itoa(expiresAt.Year()),
+	).Replace(text)
+}
+
+// Get returns the series page. Series in a group are reported to outsiders
+// as ErrNotGroupMember, like the group's markets.
+func (s *SeriesService) Get(seriesID, userID uuid.UUID) (SeriesPage, error) {
+	series, err := s.Series.Get(seriesID)
+	if err != nil {
+		return SeriesPage{}, err
+	}
+
+	if series.GroupID != nil {
+		_, err = s.MarketService.Groups.MemberRole(*series.GroupID, userID)
+		if err != nil {
+			if errors.Is(err, models.ErrNoRecord) {
+				return SeriesPage{}, ErrNotGroupMember
+			}
+			return SeriesPage{}, err
+		}
+	}
+
+	page := SeriesPage{Series: series, Schedule: series.Rule}
+
+	rule, err := recurrence.Parse(series.Rule)
+	if err == nil {
+		page.Schedule = rule.Describe()
+	}
+
+	markets, err := s.MarketService.Markets.ForSeries(seriesID)
+	if err != nil {
+		return SeriesPage{}, err
+	}
+
+	page.Markets, err = s.MarketService.withOutcomes(markets)
+	if err != nil {
+		return SeriesPage{}, err
+	}
+
+	page.Stats, err = s.Series.Stats(seriesID)
+	if err != nil {
+		return SeriesPage{}, err
+	}
+
+	if series.StoppedAt == nil {
+		page.CanStop, err = s.canStop(series, userID)
+		if err != nil {
+			return SeriesPage{}, err
+		}
+	}
+
+	return page, nil
+}
+
+// Stop ends the series so no more markets are created for it.
+func (s *SeriesService) Stop(seriesID, userID uuid.UUID) error {
+	series, err := s.Series.Get(seriesID)
+	if err != nil {
+		return err
+	}
+
+	allowed, err := s.canStop(series, userID)
+	if err != nil {
+		return err
+	}
+
+	if !allowed {
+		return ErrSeriesPermission
+	}
+
+	return s.Series.Stop(seriesID, "")
+}
+
+func (s *SeriesService) canStop(series models.Series, userID uuid.UUID) (bool, error) {
+	if userID == uuid.Nil {
+		return false, nil
+	}
+
+	if series.CreatedBy == userID {
+		return true, nil
+	}
+
+	return s.MarketService.UserService.Users.IsAdmin(userID)
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"foresee/internal/models"
+	"foresee/internal/payout"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidShares = errors.New("the outcome shares must be between 0% and 100% and add up to 100%")
+var ErrInvalidValue = errors.New("the value must be a number")
+var ErrValueRequired = errors.New("this market is resolved with a value")
+var ErrNotValueMarket = errors.New("only scalar, bucket and date markets are resolved with a value")
+
+// FullShare resolves a market entirely to one outcome.
+func FullShare(outcomeID uuid.UUID) map[uuid.UUID]int {
+	return map[uuid.UUID]int{outcomeID: payout.FullShare}
+}
+
+// ValueShares works out the shares of a market answered with value, see
+// ScalarShares and BucketShares. m must have its outcomes loaded.
+func ValueShares(m models.Market, value float64) (map[uuid.UUID]int, error) {
+	if m.Kind == models.KindScalar {
+		return ScalarShares(m, value)
+	}
+
+	return BucketShares(m, value)
+}
+
+// ResolveValue resolves a market answered with a number like ResolveMarket
+// does with the shares it works out to, and records the value itself.
+func (s *MarketService) ResolveValue(marketID uuid.UUID, userID uuid.UUID, value float64) error {
+	m, err := s.Get(marketID)
+	if err != nil {
+		return err
+	}
+
+	shares, err := ValueShares(m, value)
+	if err != nil {
+		return err
+	}
+
+	return s.resolve(marketID, userID, shares, &value)
+}
+
+// rulingShares works out the shares an admin's ruling resolves m to: value
+// for markets answered with a number, outcomeID alone otherwise. They are
+// worked out like ResolveValue does, so a ruling pays out exactly as the
+// same answer from the resolver would have.
+func (s *MarketService) rulingShares(m models.Market, outcomeID uuid.UUID, value *float64) (map[uuid.UUID]int, error) {
+	if !m.Kind.ResolvedByValue() {
+		if value != nil {
+			return nil, ErrNotValueMarket
+		}
+		return FullShare(outcomeID), nil
+	}
+
+	if value == nil {
+		return nil, ErrValueRequired
+	}
+
+	outcomes, err := s.OutcomeService.Outcomes.ForMarket(m.ID)
+	if err != nil {
+		return nil, err
+	}
+	m.Outcomes = outcomes
+
+	return ValueShares(m, *value)
+}
+
+// checkShares validates a resolution against the market's outcomes.
+func (s *MarketService) checkShares(tx *sql.Tx, marketID uuid.UUID, shares map[uuid.UUID]int) error {
+	total := 0
+	for id, bps := range shares {
+		if bps < 0 || bps > payout.FullShare {
+			return ErrInvalidShares
+		}
+		total += bps
+
+		inMarket, err := s.OutcomeService.ExistsForMarketTx(tx, id, marketID)
+		if err != nil {
+			return err
+		}
+
+		if !inMarket {
+			return models.ErrOutcomeDoesNotBelongToMarket
+		}
+	}
+
+	if total != payout.FullShare {
+		return ErrInvalidShares
+	}
+
+	return nil
+}
+
+// leadingOutcome is recorded as the market's resolved outcome. Ties go to
+// the outcome with the lowest ID so the choice does not depend on map order.
+func leadingOutcome(shares map[uuid.UUID]int) uuid.UUID {
+	var leader uuid.UUID
+	best := -1
+
+	for id, bps := range shares {
+		if bps > best || (bps == best && id.String() < leader.String()) {
+			leader, best = id, bps
+		}
+	}
+
+	return leader
+}
+
+// resolutionLabel describes a proposed or final resolution, e.g. "yes" or
+// "yes 70% · no 30%" for a split.
+func resolutionLabel(m models.Market) string {
+	var parts []string
+	leader := ""
+
+	for _, o := range m.Outcomes {
+		if o.ResolvedShareBps != nil && *o.ResolvedShareBps > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s%%", o.Label, strconv.FormatFloat(float64(*o.ResolvedShareBps)/100, 'f', -1, 64)))
+		}
+
+		if m.ProposedOutcomeID != nil && o.ID == *m.ProposedOutcomeID {
+			leader = o.Label
+		}
+	}
+
+	if len(parts) <= 1 {
+		return leader
+	}
+
+	return strings.Join(parts, " · ")
+}
+
+// Settle pays out every bet and liquidity position on the market as planned
+// by payout.Compute, credits the market's fees and marks it resolved.
+// Markets conditional on another outcome are voided.
+//
+// The market row must already be locked by tx, and callers publish
+// MarketResolved once tx has committed.
+func (s *MarketService) Settle(tx *sql.Tx, marketID uuid.UUID, shares map[uuid.UUID]int, resolvedBy uuid.UUID) ([]SettledBet, error) {
+	m, err := s.Markets.SelectForUpdate(tx, marketID)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := s.Liquidity.ForMarketForUpdate(tx, marketID)
+	if err != nil {
+		return nil, err
+	}
+
+	bets, err := s.BetService.ForMarketForUpdate(tx, marketID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := payout.Compute(stakes(positions, bets), shares, marketFees(m))
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.chargeFees(tx, m, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, p := range positions {
+		amount := plan.Lines[i].Payout()
+
+		err = s.Liquidity.SetPayout(tx, p.ID, amount)
+		if err != nil {
+			return nil, err
+		}
+
+		// Treasury liquidity is paid back by recording its payout.
+		if amount == 0 || p.UserID == nil || p.SeasonClosed {
+			continue
+		}
+
+		err = s.UserService.IncreaseBalanceBy(tx, *p.UserID, amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	settled := make([]SettledBet, len(bets))
+
+	for i, b := range bets {
+		amount := plan.Lines[len(positions)+i].Payout()
+
+		settled[i] = SettledBet{
+			BetID:              b.ID,
+			UserID:             b.UserID,
+			OutcomeID:          b.OutcomeID,
+			Amount:             b.Amount,
+			Payout:             amount,
+			ImpliedProbability: b.ImpliedProbability,
+		}
+
+		err = s.BetService.SetPayout(tx, b.ID, amount)
+		if err != nil {
+			return nil, err
+		}
+
+		// The stake was already valued when its season was archived and
+		// balances have been reset since, so the payout is only recorded.
+		if amount == 0 || b.SeasonClosed {
+			continue
+		}
+
+		err = s.UserService.IncreaseBalanceBy(tx, b.UserID, amount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.OutcomeService.Outcomes.SetResolvedShares(tx, marketID, shares)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.Markets.ResolveMarket(tx, marketID, resolvedBy, leadingOutcome(shares))
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.voidConditions(tx, marketID, leadingOutcome(shares), resolvedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	return settled, nil
+}
+
+// PayoutPreview is the settlement a resolution would make right now. Plan
+// lines cover Liquidity first and then Bets.
+type PayoutPreview struct {
+	Plan      payout.Plan
+	Liquidity []models.LiquidityPosition
+	Bets      []models.Bet
+}
+
+// PreviewResolution plans the payouts of resolving the market to shares
+// without changing anything. Only the market's resolver may preview it.
+func (s *MarketService) PreviewResolution(marketID, userID uuid.UUID, shares map[uuid.UUID]int) (PayoutPreview, error) {
+	m, err := s.Markets.Get(marketID)
+	if err != nil {
+		return PayoutPreview{}, err
+	}
+
+	allowed, err := s.CanResolve(m, userID)
+	if err != nil {
+		return PayoutPreview{}, err
+	}
+
+	if !allowed {
+		return PayoutPreview{}, models.ErrUserNotAuthorized
+	}
+
+	tx, err := s.Markets.DB.Begin()
+	if err != nil {
+		return PayoutPreview{}, err
+	}
+	defer tx.Rollback()
+
+	err = s.checkShares(tx, marketID, shares)
+	if err != nil {
+		return PayoutPreview{}, err
+	}
+
+	positions, bets, err := s.positions(marketID)
+	if err != nil {
+		return PayoutPreview{}, err
+	}
+
+	plan, err := payout.Compute(stakes(positions, bets), shares, marketFees(m))
+	if err != nil {
+		return PayoutPreview{}, err
+	}
+
+	return PayoutPreview{
+		Plan:      plan,
+		Liquidity: positions,
+		Bets:      bets,
+	}, nil
+}
+
+// positions loads the market's liquidity and bets for a preview.
+func (s *MarketService) positions(marketID uuid.UUID) ([]models.LiquidityPosition, []models.Bet, error) {
+	liquidity, err := s.Liquidity.ForMarket(marketID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	bets, err := s.BetService.Bets.ForMarket(marketID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return liquidity, bets, nil
+}
+
+// stakes lists seeded liquidity and then bets, each in the order they were
+// placed, as payout.Compute expects.
+func stakes(positions []models.LiquidityPosition, bets []models.Bet) []payout.Stake {
+	out := make([]payout.Stake, 0, len(positions)+len(bets))
+	for _, p := range positions {
+		out = append(out, payout.Stake{BetID: p.ID, OutcomeID: p.OutcomeID, Amount: p.Amount})
+	}
+
+	for _, b := range bets {
+		out = append(out, payout.Stake{BetID: b.ID, OutcomeID: b.OutcomeID, Amount: b.Amount})
+	}
+
+	return out
+}
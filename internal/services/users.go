@@ -10,46 +10,72 @@ import (
 )
 
 type UserService struct {
-	Users *models.UserModel
+	Users   *models.UserModel
+	Rewards *models.RewardScheduleModel
+	Events  *Events
 }
 
 var ErrDailyRewardNotAvailable = errors.New("daily reward already claimed")
 
-const DailyRewardAmmount = 1000
+type DailyClaim struct {
+	Amount int
+	Streak int
+}
+
+func (s *UserService) ClaimDailyReward(id uuid.UUID) (DailyClaim, error) {
+	schedule, err := s.Rewards.Get()
+	if err != nil {
+		return DailyClaim{}, err
+	}
 
-func (s *UserService) ClaimDailyReward(id uuid.UUID) error {
 	tx, err := s.Users.DB.Begin()
-	t := time.Now().UTC()
 	if err != nil {
-		return err
+		return DailyClaim{}, err
 	}
 	defer tx.Rollback()
 
 	user, err := s.Users.SelectForUpdate(tx, id)
 	if err != nil {
-		return err
+		return DailyClaim{}, err
 	}
 
-	if user.LastClaimedAt.Valid {
-		if !CanClaimReward(t, user.LastClaimedAt.Time) {
-			return ErrDailyRewardNotAvailable
-		}
+	today := ClaimDay(time.Now(), userLocation(user.Timezone))
+	if !CanClaimReward(today, user.LastClaimedAt) {
+		return DailyClaim{}, ErrDailyRewardNotAvailable
+	}
+
+	claim := DailyClaim{Streak: NextStreak(schedule, today, user.LastClaimedAt, user.RewardStreak)}
+	claim.Amount = RewardAmount(schedule, claim.Streak)
+
+	err = s.Users.ApplyDailyClaim(tx, id, user.Balance+claim.Amount, today, claim.Streak)
+	if err != nil {
+		return DailyClaim{}, err
 	}
 
-	err = s.Users.ApplyDailyClaim(tx, id, user.Balance+DailyRewardAmmount, t)
+	err = tx.Commit()
 	if err != nil {
-		return err
+		return DailyClaim{}, err
 	}
 
-	return tx.Commit()
+	s.Events.Publish(DailyRewardClaimed{
+		UserID: id,
+		Amount: claim.Amount,
+		Streak: claim.Streak,
+	})
+
+	return claim, nil
 }
 
-func (s *UserService) DecreaseBalanceBy(tx *sql.Tx, id uuid.UUID, amount int) error {
-	return s.Users.DecreaseBalanceBy(tx, id, amount)
+func (s *UserService) RewardSchedule() (models.RewardSchedule, error) {
+	return s.Rewards.Get()
 }
 
-func CanClaimReward(now time.Time, lastClaimedAt time.Time) bool {
-	return now.After(lastClaimedAt.Add(24 * time.Hour))
+func (s *UserService) UpdateRewardSchedule(rs models.RewardSchedule, adminID uuid.UUID) error {
+	return s.Rewards.Update(rs, adminID)
+}
+
+func (s *UserService) DecreaseBalanceBy(tx *sql.Tx, id uuid.UUID, amount int) error {
+	return s.Users.DecreaseBalanceBy(tx, id, amount)
 }
 
 func (s *UserService) IncreaseBalanceBy(tx *sql.Tx, userID uuid.UUID, amount int) error {
@@ -50,6 +50,10 @@ func MinChars(value string, length int) bool {
 	return utf8.RuneCountInString(value) >= length
 }
 
+func MaxChars(value string, length int) bool {
+	return utf8.RuneCountInString(value) <= length
+}
+
 func Matches(value string, rx *regexp.Regexp) bool {
 	return rx.MatchString(value)
 }
@@ -85,3 +89,12 @@ func IsValidDate(value string) bool {
 func MinNumber(number, min int) bool {
 	return number >= min
 }
+
+func IsTimezone(value string) bool {
+	if value == "" || value == "Local" {
+		return false
+	}
+
+	_, err := time.LoadLocation(value)
+	return err == nil
+}
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS user_identities;
+
+-- Accounts created through a provider have no password and are left unable
+-- to sign in with one.
+UPDATE users SET hashed_password = '' WHERE hashed_password IS NULL;
+
+ALTER TABLE IF EXISTS users ALTER COLUMN hashed_password SET NOT NULL;
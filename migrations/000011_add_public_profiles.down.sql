@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS markets_resolved_by_idx;
+DROP INDEX IF EXISTS markets_created_by_idx;
+
+ALTER TABLE IF EXISTS bets DROP COLUMN implied_probability;
+
+ALTER TABLE IF EXISTS users DROP COLUMN public_bets;
@@ -0,0 +1,8 @@
+ALTER TABLE IF EXISTS users
+    ADD COLUMN public_bets BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE IF EXISTS bets
+    ADD COLUMN implied_probability DOUBLE PRECISION NULL;
+
+CREATE INDEX markets_created_by_idx ON markets(created_by);
+CREATE INDEX markets_resolved_by_idx ON markets(resolved_by);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS bets_settled_at_idx;
+
+DROP TABLE IF EXISTS leaderboard_entries;
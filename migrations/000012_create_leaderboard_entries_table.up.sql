@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS leaderboard_entries (
+    period TEXT NOT NULL,
+    category TEXT NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id),
+    markets INTEGER NOT NULL,
+    staked BIGINT NOT NULL,
+    net_profit BIGINT NOT NULL,
+    roi DOUBLE PRECISION NOT NULL,
+    brier_score DOUBLE PRECISION NULL,
+    computed_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (period, category, user_id)
+);
+
+CREATE INDEX bets_settled_at_idx ON bets(settled_at);
@@ -0,0 +1,6 @@
+ALTER TABLE IF EXISTS bets DROP COLUMN season_id;
+
+DROP TABLE IF EXISTS season_standings;
+DROP TABLE IF EXISTS seasons;
+
+ALTER TABLE IF EXISTS users DROP COLUMN role;
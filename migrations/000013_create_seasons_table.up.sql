@@ -0,0 +1,33 @@
+ALTER TABLE IF EXISTS users
+    ADD COLUMN role TEXT NOT NULL DEFAULT 'user';
+
+CREATE TABLE IF NOT EXISTS seasons (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    starting_balance INTEGER NOT NULL,
+    status TEXT NOT NULL DEFAULT 'scheduled',
+    archived_at TIMESTAMPTZ NULL,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX seasons_status_idx ON seasons(status);
+
+CREATE TABLE IF NOT EXISTS season_standings (
+    season_id UUID NOT NULL REFERENCES seasons(id),
+    user_id UUID NOT NULL REFERENCES users(id),
+    rank INTEGER NOT NULL,
+    final_balance BIGINT NOT NULL,
+    open_stakes BIGINT NOT NULL,
+    net_profit BIGINT NOT NULL,
+    markets INTEGER NOT NULL,
+    PRIMARY KEY (season_id, user_id)
+);
+
+CREATE INDEX season_standings_user_id_idx ON season_standings(user_id);
+
+ALTER TABLE IF EXISTS bets
+    ADD COLUMN season_id UUID NULL REFERENCES seasons(id);
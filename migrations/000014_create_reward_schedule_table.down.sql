@@ -0,0 +1,14 @@
+-- Back to the UTC date each claim day starts on, see the up migration.
+UPDATE users
+SET last_daily_claim = ((
+    last_daily_claim::timestamp AT TIME ZONE (
+        CASE WHEN timezone IN (SELECT name FROM pg_timezone_names) THEN timezone ELSE 'Europe/Madrid' END
+    )
+) AT TIME ZONE 'UTC')::date
+WHERE last_daily_claim IS NOT NULL;
+
+ALTER TABLE IF EXISTS users
+    DROP COLUMN reward_streak,
+    DROP COLUMN timezone;
+
+DROP TABLE IF EXISTS reward_schedule;
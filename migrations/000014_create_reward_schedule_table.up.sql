@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS reward_schedule (
+    id BOOLEAN PRIMARY KEY DEFAULT true CHECK (id),
+    base_amount INTEGER NOT NULL,
+    streak_step_bps INTEGER NOT NULL,
+    max_amount INTEGER NOT NULL,
+    grace_days INTEGER NOT NULL,
+    updated_by UUID NULL REFERENCES users(id),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO reward_schedule (base_amount, streak_step_bps, max_amount, grace_days)
+VALUES (1000, 1000, 2000, 1);
+
+ALTER TABLE IF EXISTS users
+    ADD COLUMN reward_streak INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN timezone TEXT NOT NULL DEFAULT 'Europe/Madrid';
+
+-- Claims used to be stored as their UTC date and are now stored as the
+-- calendar day in the user's timezone. The time of day is lost, so each
+-- claim is moved to the day it would have fallen on at the end of its UTC
+-- date, which never lets anyone claim twice on the same day.
+UPDATE users
+SET last_daily_claim = ((
+    (last_daily_claim + 1)::timestamp - INTERVAL '1 second'
+) AT TIME ZONE 'UTC' AT TIME ZONE (
+    CASE WHEN timezone IN (SELECT name FROM pg_timezone_names) THEN timezone ELSE 'Europe/Madrid' END
+))::date
+WHERE last_daily_claim IS NOT NULL;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS user_achievements (
+    user_id UUID NOT NULL REFERENCES users(id),
+    code TEXT NOT NULL,
+    bonus INTEGER NOT NULL DEFAULT 0,
+    awarded_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, code)
+);
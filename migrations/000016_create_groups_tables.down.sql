@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS markets_group_id_idx;
+
+ALTER TABLE IF EXISTS markets DROP COLUMN group_id;
+
+DROP TABLE IF EXISTS group_invites;
+DROP TABLE IF EXISTS group_members;
+DROP TABLE IF EXISTS groups;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    market_id UUID NOT NULL REFERENCES markets(id),
+    user_id UUID NOT NULL REFERENCES users(id),
+    parent_id UUID NULL REFERENCES comments(id),
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    edited_at TIMESTAMPTZ NULL,
+    deleted_at TIMESTAMPTZ NULL,
+    removed_by UUID NULL REFERENCES users(id)
+);
+
+CREATE INDEX comments_market_id_idx ON comments(market_id, created_at) WHERE parent_id IS NULL;
+CREATE INDEX comments_parent_id_idx ON comments(parent_id);
@@ -0,0 +1,10 @@
+ALTER TABLE IF EXISTS users
+    DROP COLUMN suspended_reason,
+    DROP COLUMN suspended_at;
+
+ALTER TABLE IF EXISTS bets DROP COLUMN refunded_at;
+
+ALTER TABLE IF EXISTS markets DROP COLUMN hidden_at;
+
+DROP TABLE IF EXISTS moderation_actions;
+DROP TABLE IF EXISTS reports;
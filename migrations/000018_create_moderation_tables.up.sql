@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    target_type TEXT NOT NULL,
+    target_id UUID NOT NULL,
+    market_id UUID NOT NULL REFERENCES markets(id),
+    reporter_id UUID NOT NULL REFERENCES users(id),
+    reason TEXT NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'open',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    resolved_at TIMESTAMPTZ NULL,
+    resolved_by UUID NULL REFERENCES users(id),
+    UNIQUE (target_type, target_id, reporter_id)
+);
+
+CREATE INDEX reports_open_idx ON reports(target_type, target_id) WHERE status = 'open';
+
+CREATE TABLE IF NOT EXISTS moderation_actions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    moderator_id UUID NOT NULL REFERENCES users(id),
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id UUID NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX moderation_actions_created_at_idx ON moderation_actions(created_at);
+
+ALTER TABLE IF EXISTS markets
+    ADD COLUMN hidden_at TIMESTAMPTZ NULL;
+
+ALTER TABLE IF EXISTS bets
+    ADD COLUMN refunded_at TIMESTAMPTZ NULL;
+
+ALTER TABLE IF EXISTS users
+    ADD COLUMN suspended_at TIMESTAMPTZ NULL,
+    ADD COLUMN suspended_reason TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS market_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    market_id UUID NOT NULL REFERENCES markets(id),
+    revision INTEGER NOT NULL,
+    edited_by UUID NOT NULL REFERENCES users(id),
+    field TEXT NOT NULL,
+    old_value TEXT NULL,
+    new_value TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX market_revisions_market_id_idx ON market_revisions(market_id, revision);
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS disputes;
+
+DROP INDEX IF EXISTS markets_finalizes_at_idx;
+
+ALTER TABLE IF EXISTS markets
+    DROP COLUMN finalizes_at,
+    DROP COLUMN proposed_at,
+    DROP COLUMN proposed_by,
+    DROP COLUMN proposed_outcome_id,
+    DROP COLUMN dispute_window_hours;
@@ -0,0 +1,20 @@
+ALTER TABLE IF EXISTS markets
+    ADD COLUMN dispute_window_hours INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN proposed_outcome_id UUID NULL REFERENCES outcomes(id),
+    ADD COLUMN proposed_by UUID NULL REFERENCES users(id),
+    ADD COLUMN proposed_at TIMESTAMPTZ NULL,
+    ADD COLUMN finalizes_at TIMESTAMPTZ NULL;
+
+CREATE INDEX markets_finalizes_at_idx ON markets(finalizes_at) WHERE status = 'proposed';
+
+CREATE TABLE IF NOT EXISTS disputes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    market_id UUID NOT NULL REFERENCES markets(id),
+    user_id UUID NOT NULL REFERENCES users(id),
+    justification TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'open',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    decided_at TIMESTAMPTZ NULL,
+    decided_by UUID NULL REFERENCES users(id),
+    UNIQUE (market_id, user_id)
+);
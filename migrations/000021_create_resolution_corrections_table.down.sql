@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS payout_corrections;
+DROP TABLE IF EXISTS resolution_corrections;
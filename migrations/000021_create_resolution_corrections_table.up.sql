@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS resolution_corrections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    market_id UUID NOT NULL REFERENCES markets(id),
+    previous_outcome_id UUID NOT NULL REFERENCES outcomes(id),
+    outcome_id UUID NOT NULL REFERENCES outcomes(id),
+    reason TEXT NOT NULL,
+    corrected_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX resolution_corrections_market_id_idx ON resolution_corrections(market_id);
+
+CREATE TABLE IF NOT EXISTS payout_corrections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    correction_id UUID NOT NULL REFERENCES resolution_corrections(id),
+    bet_id UUID NOT NULL REFERENCES bets(id),
+    user_id UUID NOT NULL REFERENCES users(id),
+    previous_payout INTEGER NOT NULL,
+    payout INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX payout_corrections_bet_id_idx ON payout_corrections(bet_id);
@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS outcomes
+    DROP COLUMN resolved_share_bps;
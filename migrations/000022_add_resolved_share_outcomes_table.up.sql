@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS outcomes
+    ADD COLUMN resolved_share_bps INTEGER NULL;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS fee_entries;
+
+ALTER TABLE IF EXISTS markets
+    DROP COLUMN platform_fee_bps,
+    DROP COLUMN creator_fee_bps;
+
+DROP TABLE IF EXISTS fee_schedule;
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS fee_schedule (
+    id BOOLEAN PRIMARY KEY DEFAULT true CHECK (id),
+    platform_fee_bps INTEGER NOT NULL CHECK (platform_fee_bps BETWEEN 0 AND 1000),
+    creator_fee_bps INTEGER NOT NULL CHECK (creator_fee_bps BETWEEN 0 AND 1000),
+    updated_by UUID NULL REFERENCES users(id),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO fee_schedule (platform_fee_bps, creator_fee_bps)
+VALUES (200, 100);
+
+-- Markets keep the fees they were created with, so changing the schedule
+-- never alters the terms of bets already placed.
+ALTER TABLE IF EXISTS markets
+    ADD COLUMN platform_fee_bps INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN creator_fee_bps INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS fee_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    market_id UUID NOT NULL REFERENCES markets(id),
+    kind TEXT NOT NULL CHECK (kind IN ('platform', 'creator')),
+    user_id UUID NULL REFERENCES users(id),
+    amount INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX fee_entries_market_id_idx ON fee_entries(market_id);
+CREATE INDEX fee_entries_user_id_idx ON fee_entries(user_id, created_at DESC);
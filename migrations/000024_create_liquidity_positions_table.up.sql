@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS liquidity_positions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    market_id UUID NOT NULL REFERENCES markets(id),
+    outcome_id UUID NOT NULL REFERENCES outcomes(id),
+    -- NULL when the liquidity was paid for by the treasury.
+    user_id UUID NULL REFERENCES users(id),
+    season_id UUID NULL REFERENCES seasons(id),
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    payout_amount INTEGER NULL,
+    refunded_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX liquidity_positions_market_id_idx ON liquidity_positions(market_id);
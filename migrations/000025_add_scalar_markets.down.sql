@@ -0,0 +1,7 @@
+ALTER TABLE IF EXISTS markets
+    DROP CONSTRAINT IF EXISTS markets_scalar_bounds_check,
+    DROP COLUMN IF EXISTS resolved_value,
+    DROP COLUMN IF EXISTS scalar_log,
+    DROP COLUMN IF EXISTS scalar_upper,
+    DROP COLUMN IF EXISTS scalar_lower,
+    DROP COLUMN IF EXISTS kind;
@@ -0,0 +1,13 @@
+-- Scalar markets are answered with a number between their bounds. Bettors go
+-- long or short and the pool is split between the two by where the answer
+-- lands in the range.
+ALTER TABLE IF EXISTS markets
+    ADD COLUMN kind TEXT NOT NULL DEFAULT 'binary' CHECK (kind IN ('binary', 'scalar')),
+    ADD COLUMN scalar_lower DOUBLE PRECISION NULL,
+    ADD COLUMN scalar_upper DOUBLE PRECISION NULL,
+    ADD COLUMN scalar_log BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN resolved_value DOUBLE PRECISION NULL,
+    ADD CONSTRAINT markets_scalar_bounds_check CHECK (
+        kind <> 'scalar'
+        OR (scalar_lower < scalar_upper AND (NOT scalar_log OR scalar_lower > 0))
+    );
@@ -0,0 +1,12 @@
+ALTER TABLE IF EXISTS outcomes
+    DROP COLUMN IF EXISTS upper_bound,
+    DROP COLUMN IF EXISTS lower_bound,
+    DROP COLUMN IF EXISTS position;
+
+ALTER TABLE IF EXISTS markets
+    DROP CONSTRAINT IF EXISTS markets_bucket_range_check,
+    DROP COLUMN IF EXISTS bucket_size,
+    DROP COLUMN IF EXISTS bucket_upper,
+    DROP COLUMN IF EXISTS bucket_lower,
+    DROP CONSTRAINT IF EXISTS markets_kind_check,
+    ADD CONSTRAINT markets_kind_check CHECK (kind IN ('binary', 'scalar'));
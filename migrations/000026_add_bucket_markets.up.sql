@@ -0,0 +1,18 @@
+-- Bucket markets split a number or date range into ordered outcomes, each
+-- covering [lower_bound, upper_bound). The first and last buckets are open
+-- ended. Dates are stored as Unix timestamps.
+ALTER TABLE IF EXISTS markets
+    DROP CONSTRAINT IF EXISTS markets_kind_check,
+    ADD CONSTRAINT markets_kind_check CHECK (kind IN ('binary', 'scalar', 'bucket', 'date')),
+    ADD COLUMN bucket_lower DOUBLE PRECISION NULL,
+    ADD COLUMN bucket_upper DOUBLE PRECISION NULL,
+    ADD COLUMN bucket_size DOUBLE PRECISION NULL,
+    ADD CONSTRAINT markets_bucket_range_check CHECK (
+        kind NOT IN ('bucket', 'date')
+        OR (bucket_lower < bucket_upper AND bucket_size > 0)
+    );
+
+ALTER TABLE IF EXISTS outcomes
+    ADD COLUMN position INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN lower_bound DOUBLE PRECISION NULL,
+    ADD COLUMN upper_bound DOUBLE PRECISION NULL;
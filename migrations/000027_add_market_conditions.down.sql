@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS markets_condition_market_id_idx;
+
+ALTER TABLE IF EXISTS markets
+    DROP CONSTRAINT IF EXISTS markets_condition_check,
+    DROP COLUMN IF EXISTS condition_outcome_id,
+    DROP COLUMN IF EXISTS condition_market_id;
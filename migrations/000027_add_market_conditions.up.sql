@@ -0,0 +1,10 @@
+-- A conditional market only stands if its parent market resolves to
+-- condition_outcome_id, and is voided with full refunds otherwise.
+ALTER TABLE IF EXISTS markets
+    ADD COLUMN condition_market_id UUID NULL REFERENCES markets(id),
+    ADD COLUMN condition_outcome_id UUID NULL REFERENCES outcomes(id),
+    ADD CONSTRAINT markets_condition_check CHECK (
+        (condition_market_id IS NULL) = (condition_outcome_id IS NULL)
+    );
+
+CREATE INDEX markets_condition_market_id_idx ON markets(condition_market_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS markets_series_id_idx;
+
+ALTER TABLE IF EXISTS markets DROP COLUMN IF EXISTS series_id;
+
+DROP TABLE IF EXISTS market_series;
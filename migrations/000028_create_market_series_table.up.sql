@@ -0,0 +1,25 @@
+-- A market series is a template that the scheduler turns into a new market
+-- for every occurrence of its rule, see internal/recurrence. title and
+-- description may contain placeholders such as {date} and {n}.
+CREATE TABLE IF NOT EXISTS market_series (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    title TEXT NOT NULL,
+    description TEXT NOT NULL,
+    category TEXT NOT NULL,
+    resolver_type TEXT NOT NULL,
+    group_id UUID NULL REFERENCES groups(id),
+    dispute_window_hours INTEGER NOT NULL DEFAULT 0,
+    liquidity INTEGER NOT NULL DEFAULT 0,
+    rule TEXT NOT NULL,
+    next_expires_at TIMESTAMPTZ NOT NULL,
+    instances INTEGER NOT NULL DEFAULT 0,
+    created_by UUID NOT NULL REFERENCES users(id),
+    stopped_at TIMESTAMPTZ NULL,
+    stop_reason TEXT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE IF EXISTS markets
+    ADD COLUMN series_id UUID NULL REFERENCES market_series(id);
+
+CREATE INDEX markets_series_id_idx ON markets(series_id);
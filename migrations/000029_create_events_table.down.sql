@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS markets_event_id_idx;
+
+ALTER TABLE IF EXISTS markets
+    DROP CONSTRAINT IF EXISTS markets_event_group_check,
+    DROP COLUMN IF EXISTS event_group,
+    DROP COLUMN IF EXISTS event_id;
+
+DROP INDEX IF EXISTS events_starts_on_idx;
+
+DROP TABLE IF EXISTS events;
@@ -0,0 +1,20 @@
+-- An event groups related markets, e.g. every question about one
+-- tournament. Markets of an event sharing an event_group are mutually
+-- exclusive, so at most one of them can resolve yes.
+CREATE TABLE IF NOT EXISTS events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    starts_on DATE NOT NULL,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX events_starts_on_idx ON events(starts_on);
+
+ALTER TABLE IF EXISTS markets
+    ADD COLUMN event_id UUID NULL REFERENCES events(id),
+    ADD COLUMN event_group TEXT NULL,
+    ADD CONSTRAINT markets_event_group_check CHECK (event_group IS NULL OR event_id IS NOT NULL);
+
+CREATE INDEX markets_event_id_idx ON markets(event_id);
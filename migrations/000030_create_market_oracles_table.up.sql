@@ -0,0 +1,18 @@
+-- An oracle market is resolved automatically after it expires by reading a
+-- number from its data source, see internal/oracle. When every attempt
+-- fails, failed_at is set and the market waits for an admin to resolve it.
+CREATE TABLE IF NOT EXISTS market_oracles (
+    market_id UUID PRIMARY KEY REFERENCES markets(id) ON DELETE CASCADE,
+    source TEXT NOT NULL,
+    target TEXT NOT NULL,
+    path TEXT NOT NULL DEFAULT '',
+    operator TEXT NOT NULL DEFAULT '',
+    threshold DOUBLE PRECISION NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_value DOUBLE PRECISION NULL,
+    last_error TEXT NULL,
+    checked_at TIMESTAMPTZ NULL,
+    failed_at TIMESTAMPTZ NULL
+);
+
+CREATE INDEX market_oracles_failed_at_idx ON market_oracles(failed_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS notifications_user_id_created_at_idx;
+
+DROP TABLE IF EXISTS notifications;
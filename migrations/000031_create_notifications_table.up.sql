@@ -0,0 +1,13 @@
+-- Notifications tell a user about something that needs them, such as being
+-- asked to resolve a market. link points to the page to act on it.
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    message TEXT NOT NULL,
+    link TEXT NOT NULL DEFAULT '',
+    read_at TIMESTAMPTZ NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX notifications_user_id_created_at_idx ON notifications(user_id, created_at DESC);
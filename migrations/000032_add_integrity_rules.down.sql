@@ -0,0 +1,14 @@
+DELETE FROM disputes WHERE user_id IS NULL;
+
+ALTER TABLE IF EXISTS disputes
+    ALTER COLUMN user_id SET NOT NULL;
+
+DROP INDEX IF EXISTS bets_market_id_flagged_idx;
+
+ALTER TABLE IF EXISTS bets
+    DROP COLUMN IF EXISTS flagged_at;
+
+ALTER TABLE IF EXISTS markets
+    DROP COLUMN IF EXISTS integrity_rule;
+
+DROP TABLE IF EXISTS integrity_policy;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS integrity_policy (
+    id BOOLEAN PRIMARY KEY DEFAULT true CHECK (id),
+    rule TEXT NOT NULL CHECK (rule IN ('none', 'block', 'flag', 'review')),
+    updated_by UUID NULL REFERENCES users(id),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO integrity_policy (rule)
+VALUES ('review');
+
+-- Like fees, markets keep the rule they were created with so it never
+-- changes under bets already placed.
+ALTER TABLE IF EXISTS markets
+    ADD COLUMN integrity_rule TEXT NOT NULL DEFAULT 'none';
+
+ALTER TABLE IF EXISTS bets
+    ADD COLUMN flagged_at TIMESTAMPTZ NULL;
+
+CREATE INDEX bets_market_id_flagged_idx ON bets(market_id) WHERE flagged_at IS NOT NULL;
+
+-- Integrity reviews are opened by the platform rather than by a bettor.
+ALTER TABLE IF EXISTS disputes
+    ALTER COLUMN user_id DROP NOT NULL;